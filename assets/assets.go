@@ -0,0 +1,146 @@
+// Package assets provides content-hashed URLs for static assets like scripts and stylesheets,
+// with subresource integrity attributes and an [http.Handler] that serves them with immutable cache headers.
+//
+// Because the URLs change when the content does, browsers can cache the assets forever,
+// and there's no need for manual cache busting.
+package assets
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	g "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/html"
+)
+
+// Assets of an [fs.FS], with content-hashed URLs. Create them with [New].
+type Assets struct {
+	prefix string
+	byName map[string]asset
+	byPath map[string]asset
+}
+
+// asset file with its content-hashed path, integrity value, and content.
+type asset struct {
+	name      string
+	path      string
+	integrity string
+	data      []byte
+}
+
+// New reads all files in fsys and computes their content hashes, for serving under the URL path prefix,
+// like "/assets". Asset names are paths in fsys, like "js/app.js", and the hash is added before the extension,
+// like in "/assets/js/app.1a2b3c4d.js".
+// The files are read once and kept in memory, so changes to the files after New returns are not picked up.
+func New(fsys fs.FS, prefix string) (*Assets, error) {
+	a := &Assets{
+		prefix: strings.TrimSuffix(prefix, "/"),
+		byName: map[string]asset{},
+		byPath: map[string]asset{},
+	}
+
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+
+		b, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+
+		hash := sha512.Sum384(b)
+		ext := path.Ext(name)
+		as := asset{
+			name:      name,
+			path:      strings.TrimSuffix(name, ext) + "." + hex.EncodeToString(hash[:4]) + ext,
+			integrity: "sha384-" + base64.StdEncoding.EncodeToString(hash[:]),
+			data:      b,
+		}
+		a.byName[as.name] = as
+		a.byPath[as.path] = as
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("assets: error reading files: %w", err)
+	}
+
+	return a, nil
+}
+
+// URL of the asset with the given name, with the content hash.
+// It panics if there's no asset with the name, so missing assets are found the first time a page renders.
+func (a *Assets) URL(name string) string {
+	return a.prefix + "/" + a.get(name).path
+}
+
+// Integrity value of the asset with the given name, for the integrity attribute.
+// It panics if there's no asset with the name.
+func (a *Assets) Integrity(name string) string {
+	return a.get(name).integrity
+}
+
+// Script returns a script element for the asset with the given name, with the content-hashed src URL
+// and matching integrity and crossorigin attributes. Other attributes, like Defer(), can be added as children.
+// It panics if there's no asset with the name.
+func (a *Assets) Script(name string, children ...g.Node) g.Node {
+	as := a.get(name)
+	return Script(Src(a.prefix+"/"+as.path), Integrity(as.integrity), CrossOrigin("anonymous"), g.Group(children))
+}
+
+// Link returns a link element for the asset with the given name, with the content-hashed href URL
+// and matching integrity and crossorigin attributes. Add the rel attribute as a child, like Rel("stylesheet").
+// It panics if there's no asset with the name.
+func (a *Assets) Link(name string, children ...g.Node) g.Node {
+	as := a.get(name)
+	return Link(g.Group(children), Href(a.prefix+"/"+as.path), Integrity(as.integrity), CrossOrigin("anonymous"))
+}
+
+func (a *Assets) get(name string) asset {
+	as, ok := a.byName[strings.TrimPrefix(name, "/")]
+	if !ok {
+		panic(fmt.Sprintf("assets: no asset named %q", name))
+	}
+	return as
+}
+
+// ServeHTTP satisfies [http.Handler], serving the assets under the URL path prefix given to [New].
+// Content-hashed URLs are served with a Cache-Control header that lets browsers cache them forever.
+// URLs without the hash are served too, for assets that need a stable URL, but must be revalidated by browsers.
+// Mount the handler on the prefix, like with http.Handle("/assets/", a).
+func (a *Assets) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !strings.HasPrefix(r.URL.Path, a.prefix+"/") {
+		http.NotFound(w, r)
+		return
+	}
+	p := strings.TrimPrefix(r.URL.Path, a.prefix+"/")
+
+	as, ok := a.byPath[p]
+	if ok {
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	} else {
+		as, ok = a.byName[p]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Cache-Control", "no-cache")
+	}
+
+	w.Header().Set("ETag", `"`+as.integrity+`"`)
+	http.ServeContent(w, r, as.name, time.Time{}, bytes.NewReader(as.data))
+}
@@ -0,0 +1,170 @@
+package assets_test
+
+import (
+	"errors"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"maragu.dev/gomponents/assets"
+	. "maragu.dev/gomponents/html"
+	"maragu.dev/gomponents/internal/assert"
+)
+
+var files = fstest.MapFS{
+	"app.css":       {Data: []byte("body { color: hotpink; }")},
+	"js/app.min.js": {Data: []byte("console.log('party');")},
+	"LICENSE":       {Data: []byte("Party hat license")},
+}
+
+func newAssets(t *testing.T) *assets.Assets {
+	t.Helper()
+	a, err := assets.New(files, "/assets/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func TestNew(t *testing.T) {
+	t.Run("returns an error if the files can't be read", func(t *testing.T) {
+		_, err := assets.New(erroringFS{}, "/assets")
+		assert.Error(t, err)
+	})
+}
+
+func TestAssets_URL(t *testing.T) {
+	a := newAssets(t)
+
+	t.Run("adds the content hash before the extension", func(t *testing.T) {
+		for name, expected := range map[string]string{
+			"app.css":        "/assets/app.089da730.css",
+			"/js/app.min.js": "/assets/js/app.min.61d3cd75.js",
+			"LICENSE":        "/assets/LICENSE.d71f10eb",
+		} {
+			if url := a.URL(name); url != expected {
+				t.Fatalf("expected %v but got %v", expected, url)
+			}
+		}
+	})
+
+	t.Run("panics on missing assets", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r != `assets: no asset named "app.js"` {
+				t.Fatal("unexpected panic", r)
+			}
+		}()
+		a.URL("app.js")
+	})
+}
+
+func TestAssets_Script(t *testing.T) {
+	t.Run("renders a script element with the hashed URL and integrity", func(t *testing.T) {
+		a := newAssets(t)
+		assert.Equal(t, `<script src="/assets/js/app.min.61d3cd75.js" integrity="`+a.Integrity("js/app.min.js")+`" crossorigin="anonymous" defer></script>`,
+			a.Script("js/app.min.js", Defer()))
+	})
+}
+
+func TestAssets_Link(t *testing.T) {
+	t.Run("renders a link element with the hashed URL and integrity", func(t *testing.T) {
+		a := newAssets(t)
+		assert.Equal(t, `<link rel="stylesheet" href="/assets/app.089da730.css" integrity="sha384-CJ2nMDoAucOaV1fW5VBK74j/KAaMWz6pyR+pwhxvg5wm3pepLAv4bXHJL4vQnPmO" crossorigin="anonymous">`,
+			a.Link("app.css", Rel("stylesheet")))
+	})
+}
+
+func TestAssets_ServeHTTP(t *testing.T) {
+	a := newAssets(t)
+
+	t.Run("serves hashed URLs with immutable cache headers", func(t *testing.T) {
+		code, body, header := serve(t, a, http.MethodGet, a.URL("app.css"), "")
+		if code != http.StatusOK || body != "body { color: hotpink; }" {
+			t.Fatal("unexpected response", code, body)
+		}
+		if cc := header.Get("Cache-Control"); cc != "public, max-age=31536000, immutable" {
+			t.Fatal("unexpected cache control", cc)
+		}
+		if ct := header.Get("Content-Type"); ct != "text/css; charset=utf-8" {
+			t.Fatal("unexpected content type", ct)
+		}
+	})
+
+	t.Run("serves unhashed URLs without caching", func(t *testing.T) {
+		code, body, header := serve(t, a, http.MethodGet, "/assets/js/app.min.js", "")
+		if code != http.StatusOK || body != "console.log('party');" {
+			t.Fatal("unexpected response", code, body)
+		}
+		if cc := header.Get("Cache-Control"); cc != "no-cache" {
+			t.Fatal("unexpected cache control", cc)
+		}
+	})
+
+	t.Run("serves the content read in New, matching the hash", func(t *testing.T) {
+		fsys := fstest.MapFS{"app.css": {Data: []byte("body { color: hotpink; }")}}
+		a, err := assets.New(fsys, "/assets")
+		if err != nil {
+			t.Fatal(err)
+		}
+		fsys["app.css"] = &fstest.MapFile{Data: []byte("body { color: red; }")}
+
+		code, body, _ := serve(t, a, http.MethodGet, a.URL("app.css"), "")
+		if code != http.StatusOK || body != "body { color: hotpink; }" {
+			t.Fatal("unexpected response", code, body)
+		}
+	})
+
+	t.Run("responds with not modified for a matching etag", func(t *testing.T) {
+		code, _, _ := serve(t, a, http.MethodGet, a.URL("app.css"), `"`+a.Integrity("app.css")+`"`)
+		if code != http.StatusNotModified {
+			t.Fatal("unexpected status code", code)
+		}
+	})
+
+	t.Run("responds with not found for unknown assets and paths outside the prefix", func(t *testing.T) {
+		for _, path := range []string{"/assets/app.089da731.css", "/assets/", "/app.css", "/assetsapp.css"} {
+			if code, _, _ := serve(t, a, http.MethodGet, path, ""); code != http.StatusNotFound {
+				t.Fatal("unexpected status code", path, code)
+			}
+		}
+	})
+
+	t.Run("responds with method not allowed for other methods", func(t *testing.T) {
+		code, _, header := serve(t, a, http.MethodPost, a.URL("app.css"), "")
+		if code != http.StatusMethodNotAllowed || header.Get("Allow") != "GET, HEAD" {
+			t.Fatal("unexpected response", code, header)
+		}
+	})
+}
+
+func serve(t *testing.T, h http.Handler, method, path, etag string) (int, string, http.Header) {
+	t.Helper()
+	r := httptest.NewRequest(method, path, nil)
+	if etag != "" {
+		r.Header.Set("If-None-Match", etag)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w.Code, w.Body.String(), w.Header()
+}
+
+type erroringFS struct{}
+
+func (erroringFS) Open(string) (fs.File, error) {
+	return nil, errors.New("don't want to")
+}
+
+func ExampleAssets() {
+	a, err := assets.New(fstest.MapFS{"app.css": {Data: []byte("body { color: hotpink; }")}}, "/assets")
+	if err != nil {
+		panic(err)
+	}
+	// Serve the assets with http.Handle("/assets/", a)
+
+	_ = a.Link("app.css", Rel("stylesheet")).Render(os.Stdout)
+	// Output:
+	// <link rel="stylesheet" href="/assets/app.089da730.css" integrity="sha384-CJ2nMDoAucOaV1fW5VBK74j/KAaMWz6pyR+pwhxvg5wm3pepLAv4bXHJL4vQnPmO" crossorigin="anonymous">
+}
@@ -0,0 +1,159 @@
+package gomponents
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cacher stores rendered nodes for [CacheNode] and [CacheNodeWith], by key.
+// Implementations must be safe for concurrent use.
+type Cacher interface {
+	// Get the value for the key, and whether it was found and not expired.
+	Get(key string) ([]byte, bool)
+	// Set the value for the key, expiring after ttl. A ttl of zero or less means it never expires.
+	Set(key string, value []byte, ttl time.Duration)
+	// DeletePrefix deletes the values for all keys that start with prefix.
+	DeletePrefix(prefix string)
+}
+
+// DefaultCacher is the [Cacher] used by [CacheNode].
+// It's an in-memory [LRUCacher] that stores up to 1000 nodes by default.
+// Replace it before rendering anything to use another [Cacher], for example one shared between servers.
+var DefaultCacher Cacher = NewLRUCacher(1000)
+
+// CacheNode returns a [Node] that renders the [Node] returned by n, and stores the rendered bytes in [DefaultCacher]
+// under the key, so n is only called and rendered again after ttl, or when the key is deleted with DeletePrefix.
+// A ttl of zero or less means the node is cached until deleted.
+// Use it for expensive subtrees that are the same for many requests, like navigation menus or product cards.
+// The key must identify everything the node depends on, like the product ID.
+// Because the rendered bytes are shared between requests, the node is rendered with a context without
+// the values of the render context, so request-scoped values like a CSP nonce (see [WithCSPNonce]) are never cached.
+// Scripts and styles in the node don't get a nonce, so keep them out of cached nodes when using a CSP nonce.
+// The context is still cancelled with the render context.
+// If rendering fails, nothing is stored, and the error is returned.
+func CacheNode(key string, ttl time.Duration, n func() Node) Node {
+	return CacheNodeWith(DefaultCacher, key, ttl, n)
+}
+
+// CacheNodeWith is like [CacheNode], but stores the rendered bytes in the given [Cacher].
+func CacheNodeWith(c Cacher, key string, ttl time.Duration, n func() Node) Node {
+	return ContextFunc(func(ctx context.Context, w io.Writer) error {
+		if b, ok := c.Get(key); ok {
+			_, err := w.Write(b)
+			return err
+		}
+
+		var b bytes.Buffer
+		if err := RenderCtx(withoutValues{ctx}, &b, n()); err != nil {
+			return err
+		}
+		c.Set(key, b.Bytes(), ttl)
+
+		_, err := w.Write(b.Bytes())
+		return err
+	})
+}
+
+// withoutValues is a [context.Context] with the deadline and cancellation of the embedded context, but no values.
+type withoutValues struct {
+	context.Context
+}
+
+// Value satisfies [context.Context].
+func (withoutValues) Value(interface{}) interface{} {
+	return nil
+}
+
+// Compile-time check that [LRUCacher] implements [Cacher].
+var _ Cacher = (*LRUCacher)(nil)
+
+// LRUCacher is an in-memory [Cacher] that stores up to a maximum number of values,
+// evicting the least recently used value when full. Create it with [NewLRUCacher].
+type LRUCacher struct {
+	mu      sync.Mutex
+	size    int
+	list    *list.List
+	entries map[string]*list.Element
+}
+
+type lruEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// NewLRUCacher with room for size values.
+func NewLRUCacher(size int) *LRUCacher {
+	return &LRUCacher{
+		size:    size,
+		list:    list.New(),
+		entries: map[string]*list.Element{},
+	}
+}
+
+// Get satisfies [Cacher].
+func (c *LRUCacher) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := e.Value.(*lruEntry)
+	if !entry.expires.IsZero() && !time.Now().Before(entry.expires) {
+		c.remove(e)
+		return nil, false
+	}
+
+	c.list.MoveToFront(e)
+	return entry.value, true
+}
+
+// Set satisfies [Cacher].
+func (c *LRUCacher) Set(key string, value []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expires time.Time
+	if ttl > 0 {
+		expires = time.Now().Add(ttl)
+	}
+
+	if e, ok := c.entries[key]; ok {
+		entry := e.Value.(*lruEntry)
+		entry.value = value
+		entry.expires = expires
+		c.list.MoveToFront(e)
+		return
+	}
+
+	c.entries[key] = c.list.PushFront(&lruEntry{key: key, value: value, expires: expires})
+	for c.list.Len() > c.size {
+		c.remove(c.list.Back())
+	}
+}
+
+// DeletePrefix satisfies [Cacher].
+func (c *LRUCacher) DeletePrefix(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, e := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			c.remove(e)
+		}
+	}
+}
+
+// remove the list element e and its entry. The caller must hold the lock.
+func (c *LRUCacher) remove(e *list.Element) {
+	c.list.Remove(e)
+	delete(c.entries, e.Value.(*lruEntry).key)
+}
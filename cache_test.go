@@ -0,0 +1,183 @@
+package gomponents_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	g "maragu.dev/gomponents"
+	"maragu.dev/gomponents/internal/assert"
+)
+
+func TestCacheNodeWith(t *testing.T) {
+	t.Run("renders the node once and then from the cache", func(t *testing.T) {
+		c := g.NewLRUCacher(10)
+		var calls int
+		n := func() g.Node {
+			calls++
+			return g.El("nav", g.Textf("Menu %v", calls))
+		}
+
+		assert.Equal(t, `<nav>Menu 1</nav>`, g.CacheNodeWith(c, "nav", 0, n))
+		assert.Equal(t, `<nav>Menu 1</nav>`, g.CacheNodeWith(c, "nav", 0, n))
+		assert.Equal(t, `<nav>Menu 2</nav>`, g.CacheNodeWith(c, "other", 0, n))
+	})
+
+	t.Run("does not cache values from the render context", func(t *testing.T) {
+		c := g.NewLRUCacher(10)
+		n := g.CacheNodeWith(c, "script", 0, func() g.Node {
+			return g.El("script", g.Raw("party()"))
+		})
+
+		for _, nonce := range []string{"AAA", "BBB"} {
+			var b strings.Builder
+			if err := g.RenderCtx(g.WithCSPNonce(context.Background(), nonce), &b, n); err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != `<script>party()</script>` {
+				t.Fatal("unexpected output", b.String())
+			}
+		}
+	})
+
+	t.Run("renders with a context that's cancelled with the render context", func(t *testing.T) {
+		c := g.NewLRUCacher(10)
+		ctx, cancel := context.WithCancel(context.Background())
+		n := g.CacheNodeWith(c, "hat", 0, func() g.Node {
+			return g.ContextFunc(func(ctx context.Context, w io.Writer) error {
+				cancel()
+				return ctx.Err()
+			})
+		})
+		err := g.RenderCtx(ctx, &strings.Builder{}, n)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatal("unexpected error", err)
+		}
+	})
+
+	t.Run("renders the node again after the ttl", func(t *testing.T) {
+		c := g.NewLRUCacher(10)
+		var calls int
+		n := func() g.Node {
+			calls++
+			return g.Textf("%v", calls)
+		}
+
+		assert.Equal(t, `1`, g.CacheNodeWith(c, "hat", time.Millisecond, n))
+		time.Sleep(2 * time.Millisecond)
+		assert.Equal(t, `2`, g.CacheNodeWith(c, "hat", time.Millisecond, n))
+	})
+
+	t.Run("does not cache on render error", func(t *testing.T) {
+		c := g.NewLRUCacher(10)
+		n := g.CacheNodeWith(c, "hat", 0, func() g.Node {
+			return g.NodeFunc(func(io.Writer) error {
+				return errors.New("no party")
+			})
+		})
+		err := n.Render(&strings.Builder{})
+		assert.Error(t, err)
+		if _, ok := c.Get("hat"); ok {
+			t.Fatal("cached")
+		}
+	})
+
+	t.Run("returns render error on cannot write", func(t *testing.T) {
+		c := g.NewLRUCacher(10)
+		n := g.CacheNodeWith(c, "hat", 0, func() g.Node { return g.El("div") })
+		for i := 0; i < 2; i++ {
+			t.Run(fmt.Sprintf("render %v", i), func(t *testing.T) {
+				err := n.Render(&erroringWriter{})
+				assert.Error(t, err)
+			})
+		}
+	})
+
+	t.Run("is safe for concurrent use", func(t *testing.T) {
+		c := g.NewLRUCacher(10)
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				n := g.CacheNodeWith(c, fmt.Sprintf("hat%v", i%20), 0, func() g.Node { return g.Text("party") })
+				_ = n.Render(&strings.Builder{})
+				c.DeletePrefix("hat1")
+			}(i)
+		}
+		wg.Wait()
+	})
+}
+
+func TestCacheNode(t *testing.T) {
+	t.Run("uses the default cacher", func(t *testing.T) {
+		n := g.CacheNode("cache-test", 0, func() g.Node { return g.Text("party") })
+		assert.Equal(t, `party`, n)
+		if b, ok := g.DefaultCacher.Get("cache-test"); !ok || string(b) != "party" {
+			t.Fatal("not cached")
+		}
+		g.DefaultCacher.DeletePrefix("cache-test")
+	})
+}
+
+func TestLRUCacher(t *testing.T) {
+	t.Run("evicts the least recently used value when full", func(t *testing.T) {
+		c := g.NewLRUCacher(2)
+		c.Set("a", []byte("a"), 0)
+		c.Set("b", []byte("b"), 0)
+		_, _ = c.Get("a")
+		c.Set("c", []byte("c"), 0)
+
+		if _, ok := c.Get("b"); ok {
+			t.Fatal("b not evicted")
+		}
+		for _, key := range []string{"a", "c"} {
+			if v, ok := c.Get(key); !ok || string(v) != key {
+				t.Fatal("unexpected value for", key, string(v))
+			}
+		}
+	})
+
+	t.Run("replaces existing values", func(t *testing.T) {
+		c := g.NewLRUCacher(2)
+		c.Set("a", []byte("a"), time.Millisecond)
+		c.Set("a", []byte("b"), 0)
+		time.Sleep(2 * time.Millisecond)
+		if v, ok := c.Get("a"); !ok || string(v) != "b" {
+			t.Fatal("unexpected value", string(v))
+		}
+	})
+
+	t.Run("deletes by key prefix", func(t *testing.T) {
+		c := g.NewLRUCacher(10)
+		c.Set("product:1", []byte("1"), 0)
+		c.Set("product:2", []byte("2"), 0)
+		c.Set("nav", []byte("nav"), 0)
+		c.DeletePrefix("product:")
+
+		_, ok1 := c.Get("product:1")
+		_, ok2 := c.Get("product:2")
+		_, ok3 := c.Get("nav")
+		if ok1 || ok2 || !ok3 {
+			t.FailNow()
+		}
+	})
+}
+
+func ExampleCacheNode() {
+	menu := func() g.Node {
+		return g.CacheNode("menu", time.Minute, func() g.Node {
+			// Build an expensive menu here
+			return g.El("nav", g.El("a", g.Attr("href", "/"), g.Text("Home")))
+		})
+	}
+	_ = menu().Render(os.Stdout)
+	g.DefaultCacher.DeletePrefix("menu")
+	// Output: <nav><a href="/">Home</a></nav>
+}
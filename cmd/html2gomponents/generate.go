@@ -0,0 +1,511 @@
+package main
+
+import (
+	"go/format"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	g "maragu.dev/gomponents"
+)
+
+// Options for [generate].
+type Options struct {
+	// Package name of the generated file.
+	Package string
+	// Func is the name of the generated function that returns the [g.Node].
+	Func string
+	// Alias uses the g and h import aliases instead of dot imports.
+	Alias bool
+}
+
+// generate Go source for a function returning the given nodes, using the helpers of the html package where possible.
+func generate(nodes []g.Node, opts Options) ([]byte, error) {
+	gen := &generator{alias: opts.Alias}
+
+	nodes = gen.prepare(nodes, false)
+	var expr string
+	switch {
+	case len(nodes) == 2 && isDoctype(nodes[0]) && isElement(nodes[1], "html"):
+		expr = gen.h("Doctype") + "(" + gen.node(nodes[1], false) + ")"
+	case len(nodes) == 1:
+		expr = gen.node(nodes[0], false)
+	default:
+		expr = gen.g("Group") + "{\n"
+		for _, n := range nodes {
+			expr += gen.node(n, false) + ",\n"
+		}
+		expr += "}"
+	}
+
+	var b strings.Builder
+	b.WriteString("package " + opts.Package + "\n\n")
+	b.WriteString("import (\n")
+	if gen.usesG || gen.alias {
+		// The return type always uses the gomponents package
+		b.WriteString(gen.importLine("g", "maragu.dev/gomponents"))
+	}
+	if gen.usesH {
+		b.WriteString(gen.importLine("h", "maragu.dev/gomponents/html"))
+	}
+	b.WriteString(")\n\n")
+	b.WriteString("func " + opts.Func + "() " + gen.nodeType() + " {\n")
+	b.WriteString("return " + expr + "\n")
+	b.WriteString("}\n")
+
+	return format.Source([]byte(b.String()))
+}
+
+// generator of Go expressions for nodes.
+type generator struct {
+	alias bool
+	usesG bool
+	usesH bool
+}
+
+// g returns the identifier name from the gomponents package.
+func (gen *generator) g(name string) string {
+	gen.usesG = true
+	if gen.alias {
+		return "g." + name
+	}
+	return name
+}
+
+// h returns the identifier name from the html package.
+func (gen *generator) h(name string) string {
+	gen.usesH = true
+	if gen.alias {
+		return "h." + name
+	}
+	return name
+}
+
+// nodeType is the return type of the generated function.
+func (gen *generator) nodeType() string {
+	if gen.alias {
+		return "g.Node"
+	}
+	gen.usesG = true
+	return "Node"
+}
+
+func (gen *generator) importLine(alias, path string) string {
+	if gen.alias {
+		return alias + " " + strconv.Quote(path) + "\n"
+	}
+	return ". " + strconv.Quote(path) + "\n"
+}
+
+// node returns a Go expression for n.
+// If preserve is true, whitespace in text is kept as is.
+func (gen *generator) node(n g.Node, preserve bool) string {
+	switch n := n.(type) {
+	case g.ElementNode:
+		return gen.element(n, preserve)
+	case g.AttributeNode:
+		return gen.attribute(n)
+	case g.TextNode:
+		return gen.g("Text") + "(" + quote(string(n)) + ")"
+	case g.RawNode:
+		return gen.g("Raw") + "(" + quote(string(n)) + ")"
+	default:
+		// Other nodes can't be inspected, so render them as raw HTML
+		var b strings.Builder
+		_ = n.Render(&b)
+		return gen.g("Raw") + "(" + quote(b.String()) + ")"
+	}
+}
+
+// element returns a Go expression for e, with attributes on the first line and other children on their own lines.
+// A single text child goes on the first line as well.
+func (gen *generator) element(e g.ElementNode, preserve bool) string {
+	var start string
+	if helper, ok := elements[e.Name]; ok {
+		start = gen.h(helper) + "("
+	} else {
+		start = gen.g("El") + "(" + strconv.Quote(e.Name)
+		if len(e.Children) > 0 {
+			start += ", "
+		}
+	}
+
+	switch e.Name {
+	case "pre", "textarea", "script", "style":
+		preserve = true
+	}
+
+	var attrs, children []string
+	inline := true
+	for _, c := range gen.prepare(e.Children, preserve) {
+		if a, ok := c.(g.AttributeNode); ok {
+			attrs = append(attrs, gen.attribute(a))
+			continue
+		}
+		if _, ok := c.(g.ElementNode); ok {
+			inline = false
+		}
+		children = append(children, gen.node(c, preserve))
+	}
+
+	s := start + strings.Join(attrs, ", ")
+	switch {
+	case len(children) == 0:
+		return s + ")"
+	case len(children) == 1 && inline && !strings.Contains(children[0], "\n"):
+		if len(attrs) > 0 {
+			s += ", "
+		}
+		return s + children[0] + ")"
+	default:
+		if len(attrs) > 0 {
+			s += ","
+		}
+		return s + "\n" + strings.Join(children, ",\n") + ",\n)"
+	}
+}
+
+// attribute returns a Go expression for a.
+func (gen *generator) attribute(a g.AttributeNode) string {
+	if helper, ok := booleanAttributes[a.Name]; ok && (a.Boolean || a.Value == "") {
+		return gen.h(helper) + "()"
+	}
+
+	if a.Boolean {
+		if a.Name == "popover" {
+			return gen.h("Popover") + "()"
+		}
+		return gen.g("Attr") + "(" + strconv.Quote(a.Name) + ")"
+	}
+
+	if helper, ok := valueAttributes[a.Name]; ok {
+		// The helper would sanitize values that aren't safe, so those are kept as they are without it
+		switch sanitizedAttributes[a.Name] {
+		case "URLAttr":
+			if isSanitized(g.URLAttr(a.Name, a.Value), a.Value) {
+				return gen.g("SafeURLAttr") + "(" + strconv.Quote(a.Name) + ", " + gen.g("SafeURL") + "(" + quote(a.Value) + "))"
+			}
+		case "CSSAttr":
+			if isSanitized(g.CSSAttr(a.Name, a.Value), a.Value) {
+				return gen.g("Attr") + "(" + strconv.Quote(a.Name) + ", " + quote(a.Value) + ")"
+			}
+		}
+		return gen.h(helper) + "(" + quote(a.Value) + ")"
+	}
+
+	switch {
+	case a.Name == "popover":
+		return gen.h("Popover") + "(" + quote(a.Value) + ")"
+	case strings.HasPrefix(a.Name, "data-") && len(a.Name) > len("data-"):
+		return gen.h("Data") + "(" + strconv.Quote(strings.TrimPrefix(a.Name, "data-")) + ", " + quote(a.Value) + ")"
+	case strings.HasPrefix(a.Name, "aria-") && len(a.Name) > len("aria-"):
+		return gen.h("Aria") + "(" + strconv.Quote(strings.TrimPrefix(a.Name, "aria-")) + ", " + quote(a.Value) + ")"
+	default:
+		return gen.g("Attr") + "(" + strconv.Quote(a.Name) + ", " + quote(a.Value) + ")"
+	}
+}
+
+// isSanitized reports whether the attribute node n has a different value than the given one.
+func isSanitized(n g.Node, value string) bool {
+	return n.(g.AttributeNode).Value != value
+}
+
+// prepare nodes for generation by flattening groups and, if preserve is false, collapsing formatting whitespace.
+// Whitespace that includes a newline is formatting whitespace, which is collapsed into a single space,
+// or left out if it's all there is to a text node.
+// Browsers render the result the same, since they collapse whitespace anyway.
+func (gen *generator) prepare(nodes []g.Node, preserve bool) []g.Node {
+	var result []g.Node
+	for _, n := range nodes {
+		switch v := n.(type) {
+		case nil:
+			continue
+		case g.Group:
+			result = append(result, gen.prepare(v, preserve)...)
+		case g.TextNode:
+			if preserve {
+				result = append(result, v)
+				continue
+			}
+			if strings.TrimSpace(string(v)) == "" && strings.Contains(string(v), "\n") {
+				continue
+			}
+			result = append(result, g.TextNode(collapseFormattingWhitespace(string(v))))
+		default:
+			result = append(result, n)
+		}
+	}
+	return result
+}
+
+// collapseFormattingWhitespace replaces runs of whitespace that include a newline with a single space.
+func collapseFormattingWhitespace(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if !unicode.IsSpace(r) {
+			b.WriteString(s[i : i+size])
+			i += size
+			continue
+		}
+
+		j := i
+		for j < len(s) {
+			r, size := utf8.DecodeRuneInString(s[j:])
+			if !unicode.IsSpace(r) {
+				break
+			}
+			j += size
+		}
+		if run := s[i:j]; strings.Contains(run, "\n") {
+			b.WriteString(" ")
+		} else {
+			b.WriteString(run)
+		}
+		i = j
+	}
+	return b.String()
+}
+
+// quote s as a Go string literal, using a raw string literal for multi-line strings and strings with double quotes.
+func quote(s string) string {
+	if (strings.Contains(s, "\n") || strings.Contains(s, `"`)) && canBackquote(s) {
+		return "`" + s + "`"
+	}
+	return strconv.Quote(s)
+}
+
+// canBackquote reports whether s can be a raw string literal without changing it.
+func canBackquote(s string) bool {
+	for _, r := range s {
+		if r == '`' || r == '\r' || r == utf8.RuneError || r == '\uFEFF' || (r != '\n' && r != '\t' && !unicode.IsPrint(r)) {
+			return false
+		}
+	}
+	return true
+}
+
+func isDoctype(n g.Node) bool {
+	r, ok := n.(g.RawNode)
+	return ok && strings.EqualFold(string(r), "<!doctype html>")
+}
+
+func isElement(n g.Node, name string) bool {
+	e, ok := n.(g.ElementNode)
+	return ok && e.Name == name
+}
+
+// elements are the element names with helpers in the html package.
+var elements = map[string]string{
+	"a":          "A",
+	"abbr":       "Abbr",
+	"address":    "Address",
+	"area":       "Area",
+	"article":    "Article",
+	"aside":      "Aside",
+	"audio":      "Audio",
+	"b":          "B",
+	"base":       "Base",
+	"blockquote": "BlockQuote",
+	"body":       "Body",
+	"br":         "Br",
+	"button":     "Button",
+	"canvas":     "Canvas",
+	"caption":    "Caption",
+	"cite":       "Cite",
+	"code":       "Code",
+	"col":        "Col",
+	"colgroup":   "ColGroup",
+	"data":       "DataEl",
+	"datalist":   "DataList",
+	"dd":         "Dd",
+	"del":        "Del",
+	"details":    "Details",
+	"dfn":        "Dfn",
+	"dialog":     "Dialog",
+	"div":        "Div",
+	"dl":         "Dl",
+	"dt":         "Dt",
+	"em":         "Em",
+	"embed":      "Embed",
+	"fieldset":   "FieldSet",
+	"figcaption": "FigCaption",
+	"figure":     "Figure",
+	"footer":     "Footer",
+	"form":       "Form",
+	"h1":         "H1",
+	"h2":         "H2",
+	"h3":         "H3",
+	"h4":         "H4",
+	"h5":         "H5",
+	"h6":         "H6",
+	"head":       "Head",
+	"header":     "Header",
+	"hgroup":     "HGroup",
+	"hr":         "Hr",
+	"html":       "HTML",
+	"i":          "I",
+	"iframe":     "IFrame",
+	"img":        "Img",
+	"input":      "Input",
+	"ins":        "Ins",
+	"kbd":        "Kbd",
+	"label":      "Label",
+	"legend":     "Legend",
+	"li":         "Li",
+	"link":       "Link",
+	"main":       "Main",
+	"mark":       "Mark",
+	"menu":       "Menu",
+	"meta":       "Meta",
+	"meter":      "Meter",
+	"nav":        "Nav",
+	"noscript":   "NoScript",
+	"object":     "Object",
+	"ol":         "Ol",
+	"optgroup":   "OptGroup",
+	"option":     "Option",
+	"output":     "Output",
+	"p":          "P",
+	"param":      "Param",
+	"picture":    "Picture",
+	"pre":        "Pre",
+	"progress":   "Progress",
+	"q":          "Q",
+	"rp":         "Rp",
+	"rt":         "Rt",
+	"ruby":       "Ruby",
+	"s":          "S",
+	"samp":       "Samp",
+	"script":     "Script",
+	"search":     "Search",
+	"section":    "Section",
+	"select":     "Select",
+	"slot":       "SlotEl",
+	"small":      "Small",
+	"source":     "Source",
+	"span":       "Span",
+	"strong":     "Strong",
+	"style":      "StyleEl",
+	"sub":        "Sub",
+	"summary":    "Summary",
+	"sup":        "Sup",
+	"svg":        "SVG",
+	"table":      "Table",
+	"tbody":      "TBody",
+	"td":         "Td",
+	"template":   "Template",
+	"textarea":   "Textarea",
+	"tfoot":      "TFoot",
+	"th":         "Th",
+	"thead":      "THead",
+	"time":       "Time",
+	"title":      "TitleEl",
+	"tr":         "Tr",
+	"track":      "Track",
+	"u":          "U",
+	"ul":         "Ul",
+	"var":        "Var",
+	"video":      "Video",
+	"wbr":        "Wbr",
+}
+
+// booleanAttributes are the attribute names with boolean helpers in the html package.
+var booleanAttributes = map[string]string{
+	"async":          "Async",
+	"autofocus":      "AutoFocus",
+	"autoplay":       "AutoPlay",
+	"checked":        "Checked",
+	"controls":       "Controls",
+	"defer":          "Defer",
+	"disabled":       "Disabled",
+	"formnovalidate": "FormNoValidate",
+	"loop":           "Loop",
+	"multiple":       "Multiple",
+	"muted":          "Muted",
+	"open":           "Open",
+	"playsinline":    "PlaysInline",
+	"readonly":       "ReadOnly",
+	"required":       "Required",
+	"selected":       "Selected",
+}
+
+// sanitizedAttributes are the value attributes with helpers that sanitize the value,
+// mapped to the function in the gomponents package that the helper uses.
+var sanitizedAttributes = map[string]string{
+	"action":     "URLAttr",
+	"cite":       "URLAttr",
+	"formaction": "URLAttr",
+	"href":       "URLAttr",
+	"poster":     "URLAttr",
+	"src":        "URLAttr",
+	"style":      "CSSAttr",
+}
+
+// valueAttributes are the attribute names with name-value helpers in the html package.
+var valueAttributes = map[string]string{
+	"accept":              "Accept",
+	"action":              "Action",
+	"alt":                 "Alt",
+	"as":                  "As",
+	"autocomplete":        "AutoComplete",
+	"charset":             "Charset",
+	"cite":                "CiteAttr",
+	"class":               "Class",
+	"cols":                "Cols",
+	"colspan":             "ColSpan",
+	"content":             "Content",
+	"crossorigin":         "CrossOrigin",
+	"datetime":            "DateTime",
+	"dir":                 "Dir",
+	"download":            "Download",
+	"draggable":           "Draggable",
+	"enctype":             "EncType",
+	"for":                 "For",
+	"form":                "FormAttr",
+	"formaction":          "FormAction",
+	"formenctype":         "FormEncType",
+	"formmethod":          "FormMethod",
+	"formtarget":          "FormTarget",
+	"height":              "Height",
+	"hidden":              "Hidden",
+	"href":                "Href",
+	"id":                  "ID",
+	"integrity":           "Integrity",
+	"label":               "LabelAttr",
+	"lang":                "Lang",
+	"list":                "List",
+	"loading":             "Loading",
+	"max":                 "Max",
+	"maxlength":           "MaxLength",
+	"method":              "Method",
+	"min":                 "Min",
+	"minlength":           "MinLength",
+	"name":                "Name",
+	"pattern":             "Pattern",
+	"placeholder":         "Placeholder",
+	"popovertarget":       "PopoverTarget",
+	"popovertargetaction": "PopoverTargetAction",
+	"poster":              "Poster",
+	"preload":             "Preload",
+	"referrerpolicy":      "ReferrerPolicy",
+	"rel":                 "Rel",
+	"role":                "Role",
+	"rows":                "Rows",
+	"rowspan":             "RowSpan",
+	"scope":               "Scope",
+	"sizes":               "Sizes",
+	"slot":                "SlotAttr",
+	"spellcheck":          "SpellCheck",
+	"src":                 "Src",
+	"srcset":              "SrcSet",
+	"step":                "Step",
+	"style":               "Style",
+	"tabindex":            "TabIndex",
+	"target":              "Target",
+	"title":               "Title",
+	"type":                "Type",
+	"value":               "Value",
+	"width":               "Width",
+}
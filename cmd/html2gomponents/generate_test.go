@@ -0,0 +1,306 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"maragu.dev/gomponents/parse"
+)
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		opts     Options
+		expected string
+	}{
+		{
+			name:  "uses html helpers with dot imports",
+			input: `<div class="hat" id="party"><a href="/hats">Hats &amp; more</a><input required type="text"></div>`,
+			opts:  Options{Package: "html", Func: "Page"},
+			expected: `package html
+
+import (
+	. "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/html"
+)
+
+func Page() Node {
+	return Div(Class("hat"), ID("party"),
+		A(Href("/hats"), Text("Hats & more")),
+		Input(Required(), Type("text")),
+	)
+}
+`,
+		},
+		{
+			name:  "uses import aliases",
+			input: `<p title="Hat">Party</p><p>Time</p>`,
+			opts:  Options{Package: "views", Func: "Party", Alias: true},
+			expected: `package views
+
+import (
+	g "maragu.dev/gomponents"
+	h "maragu.dev/gomponents/html"
+)
+
+func Party() g.Node {
+	return g.Group{
+		h.P(h.Title("Hat"), g.Text("Party")),
+		h.P(g.Text("Time")),
+	}
+}
+`,
+		},
+		{
+			name:  "resolves naming collisions between elements and attributes",
+			input: `<title>Hat</title><style>a{}</style><form><label form="f" label="l">A</label><data value="1"></data><cite cite="/c"></cite></form><span style="x" title="y" slot="z" data-x="1"></span><slot></slot>`,
+			opts:  Options{Package: "html", Func: "Page"},
+			expected: `package html
+
+import (
+	. "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/html"
+)
+
+func Page() Node {
+	return Group{
+		TitleEl(Text("Hat")),
+		StyleEl(Raw("a{}")),
+		Form(
+			Label(FormAttr("f"), LabelAttr("l"), Text("A")),
+			DataEl(Value("1")),
+			Cite(CiteAttr("/c")),
+		),
+		Span(Style("x"), Title("y"), SlotAttr("z"), Data("x", "1")),
+		SlotEl(),
+	}
+}
+`,
+		},
+		{
+			name:  "falls back to El and Attr without helpers",
+			input: `<my-element hx-get="/hat" aria-label="Hat" itemscope popover="manual" hidden></my-element>`,
+			opts:  Options{Package: "html", Func: "Page"},
+			expected: `package html
+
+import (
+	. "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/html"
+)
+
+func Page() Node {
+	return El("my-element", Attr("hx-get", "/hat"), Aria("label", "Hat"), Attr("itemscope"), Popover("manual"), Attr("hidden"))
+}
+`,
+		},
+		{
+			name:  "keeps values that the helpers would sanitize",
+			input: `<a href="javascript:void(0)" style="color: red"><img src="data:image/png;base64,AAAA" style="background: url(javascript:x)"></a>`,
+			opts:  Options{Package: "html", Func: "Page"},
+			expected: `package html
+
+import (
+	. "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/html"
+)
+
+func Page() Node {
+	return A(SafeURLAttr("href", SafeURL("javascript:void(0)")), Style("color: red"),
+		Img(SafeURLAttr("src", SafeURL("data:image/png;base64,AAAA")), Attr("style", "background: url(javascript:x)")),
+	)
+}
+`,
+		},
+		{
+			name:  "only imports the html package if used",
+			input: `<my-element></my-element>`,
+			opts:  Options{Package: "html", Func: "Page", Alias: true},
+			expected: `package html
+
+import (
+	g "maragu.dev/gomponents"
+)
+
+func Page() g.Node {
+	return g.El("my-element")
+}
+`,
+		},
+		{
+			name:  "wraps a document in Doctype and collapses formatting whitespace",
+			input: "<!DOCTYPE html>\n<html>\n  <body>\n    <p>\n      Party   hat\n    </p>\n    <pre>\n  keep \"this\"\n</pre>\n  </body>\n</html>\n",
+			opts:  Options{Package: "html", Func: "Page"},
+			expected: "package html\n\nimport (\n\t. \"maragu.dev/gomponents\"\n\t. \"maragu.dev/gomponents/html\"\n)\n\n" +
+				"func Page() Node {\n\treturn Doctype(HTML(\n\t\tBody(\n\t\t\tP(Text(\" Party   hat \")),\n\t\t\tPre(\n\t\t\t\tText(`\n  keep \"this\"\n`),\n\t\t\t),\n\t\t),\n\t))\n}\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			nodes, err := parse.HTML(strings.NewReader(test.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual, err := generate(nodes, test.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(actual) != test.expected {
+				t.Fatalf("expected\n%v\nbut got\n%v", test.expected, string(actual))
+			}
+		})
+	}
+}
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		input, expected string
+	}{
+		{"hat", `"hat"`},
+		{`"hat"`, "`\"hat\"`"},
+		{"party\nhat", "`party\nhat`"},
+		{"`hat`\n", `"` + "`hat`" + `\n"`},
+		{"hat\r\n", `"hat\r\n"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			if actual := quote(test.input); actual != test.expected {
+				t.Fatalf("expected %v but got %v", test.expected, actual)
+			}
+		})
+	}
+}
+
+// TestHelpers checks that the helper tables match the non-deprecated helpers in the html package.
+func TestHelpers(t *testing.T) {
+	actualElements := map[string]string{}
+	actualBooleanAttributes := map[string]string{}
+	actualValueAttributes := map[string]string{}
+	actualSanitizedAttributes := map[string]string{}
+
+	files, err := filepath.Glob("../../html/*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(token.NewFileSet(), file, nil, parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, decl := range f.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil || len(fn.Body.List) != 1 {
+				continue
+			}
+			if fn.Doc != nil && strings.Contains(fn.Doc.Text(), "Deprecated:") {
+				continue
+			}
+			ret, ok := fn.Body.List[0].(*ast.ReturnStmt)
+			if !ok || len(ret.Results) != 1 {
+				continue
+			}
+			call, ok := ret.Results[0].(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				continue
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				continue
+			}
+			lit, ok := call.Args[0].(*ast.BasicLit)
+			if !ok {
+				// Prefixed attributes like Aria and Data
+				continue
+			}
+			name, err := strconv.Unquote(lit.Value)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			params := fn.Type.Params.List
+			switch {
+			case sel.Sel.Name == "El":
+				actualElements[name] = fn.Name.Name
+			case sel.Sel.Name == "Attr" && len(params) == 0:
+				actualBooleanAttributes[name] = fn.Name.Name
+			case isValueAttr(sel.Sel.Name) && len(params) == 1 && len(params[0].Names) == 1 && params[0].Names[0].Name == "v":
+				actualValueAttributes[name] = fn.Name.Name
+				if sel.Sel.Name != "Attr" {
+					actualSanitizedAttributes[name] = sel.Sel.Name
+				}
+			}
+		}
+	}
+
+	requireEqualMaps(t, "elements", elements, actualElements)
+	requireEqualMaps(t, "boolean attributes", booleanAttributes, actualBooleanAttributes)
+	requireEqualMaps(t, "value attributes", valueAttributes, actualValueAttributes)
+	requireEqualMaps(t, "sanitized attributes", sanitizedAttributes, actualSanitizedAttributes)
+}
+
+// isValueAttr reports whether the named function in the core package creates a name-value attribute.
+func isValueAttr(name string) bool {
+	return name == "Attr" || name == "URLAttr" || name == "CSSAttr"
+}
+
+func requireEqualMaps(t *testing.T, name string, expected, actual map[string]string) {
+	t.Helper()
+
+	for k, v := range actual {
+		if expected[k] != v {
+			t.Errorf("%v: expected %v to map to %v, but got %v", name, k, v, expected[k])
+		}
+	}
+	for k := range expected {
+		if _, ok := actual[k]; !ok {
+			t.Errorf("%v: %v has no helper in the html package", name, k)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	t.Run("reads from stdin and writes to stdout", func(t *testing.T) {
+		var b strings.Builder
+		if err := run([]string{"-alias", "-func", "Hat"}, strings.NewReader(`<br>`), &b); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(b.String(), "func Hat() g.Node {\n\treturn h.Br()\n}") {
+			t.Fatal("unexpected output", b.String())
+		}
+	})
+
+	t.Run("reads from a file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "hat.html")
+		if err := os.WriteFile(path, []byte(`<hr>`), 0600); err != nil {
+			t.Fatal(err)
+		}
+		var b strings.Builder
+		if err := run([]string{"-package", "views", path}, strings.NewReader(""), &b); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(b.String(), "package views\n") || !strings.Contains(b.String(), "return Hr()") {
+			t.Fatal("unexpected output", b.String())
+		}
+	})
+
+	t.Run("errors on missing file and too many files", func(t *testing.T) {
+		if err := run([]string{"does-not-exist.html"}, nil, nil); err == nil {
+			t.Fatal("no error on missing file")
+		}
+		if err := run([]string{"a.html", "b.html"}, nil, nil); err == nil {
+			t.Fatal("no error on too many files")
+		}
+	})
+}
@@ -0,0 +1,75 @@
+// Command html2gomponents converts HTML into Go source code using gomponents.
+//
+// Usage:
+//
+//	html2gomponents [flags] [file]
+//
+// The HTML is read from the given file, or from stdin if no file is given, and the Go source is written to stdout.
+// Elements and attributes use the helpers of the html package where they exist, and [g.El] and [g.Attr] otherwise.
+//
+// The flags are:
+//
+//	-alias
+//		Use the g and h import aliases instead of dot imports.
+//	-func name
+//		Name of the generated function returning the node (default "Page").
+//	-package name
+//		Package name of the generated file (default "html").
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"maragu.dev/gomponents/parse"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, "html2gomponents:", err)
+		os.Exit(1)
+	}
+}
+
+// run the command with the given arguments, reading from stdin if no file is given, and writing to stdout.
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("html2gomponents", flag.ContinueOnError)
+	var opts Options
+	fs.BoolVar(&opts.Alias, "alias", false, "use the g and h import aliases instead of dot imports")
+	fs.StringVar(&opts.Func, "func", "Page", "name of the generated function returning the node")
+	fs.StringVar(&opts.Package, "package", "html", "package name of the generated file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	r := stdin
+	switch fs.NArg() {
+	case 0:
+	case 1:
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+		r = f
+	default:
+		return fmt.Errorf("expected at most one file, got %v", fs.NArg())
+	}
+
+	nodes, err := parse.HTML(r)
+	if err != nil {
+		return fmt.Errorf("error parsing HTML: %w", err)
+	}
+
+	src, err := generate(nodes, opts)
+	if err != nil {
+		return fmt.Errorf("error generating Go source: %w", err)
+	}
+
+	_, err = stdout.Write(src)
+	return err
+}
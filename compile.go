@@ -0,0 +1,235 @@
+package gomponents
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// StaticNode returns a [Node] that renders n once, right away, and then writes the same bytes on every render.
+// Use it for large subtrees that never change, like a site footer, to avoid walking and escaping them again.
+// Everything in n is rendered only once, including nodes like [NodeFunc], and without a context,
+// so n must not depend on request data or the render context, like a CSP nonce (see [WithCSPNonce]).
+// Use [CompileNode] for trees that are only mostly static.
+// If n can't be rendered, it's rendered normally every time instead, so the error is returned on render.
+// If n is a [Group], its attributes and elements are kept apart, so the result can be used in the same places.
+func StaticNode(n Node) Node {
+	if n == nil {
+		return nil
+	}
+
+	if g, ok := n.(Group); ok {
+		return Group{staticGroup(g, AttributeType), staticGroup(g, ElementType)}
+	}
+
+	var b bytes.Buffer
+	if err := n.Render(&b); err != nil {
+		return compiledNode{chunks: []compiledChunk{{n: n}}, typ: nodeType(n)}
+	}
+	return compiledNode{chunks: []compiledChunk{{b: b.Bytes()}}, typ: nodeType(n)}
+}
+
+// CompileNode returns a [Node] that renders the same as n, but faster, by rendering the static parts of n once,
+// right away, and the dynamic parts on every render.
+// The static parts are [ElementNode], [AttributeNode], [TextNode], [RawNode], [TrustedScript], and [Group],
+// so a mostly static tree renders as a few writes of bytes, with the dynamic parts in between.
+// The dynamic parts are nodes that can't be inspected, like [NodeFunc], [ContextFunc], and custom [Node] implementations,
+// as well as script, style, and stylesheet link elements, which can get a CSP nonce from the render context.
+// Dynamic parts are rendered with the render context, see [RenderCtx].
+//
+// Compile the tree once, for example in a package-level variable, and render the result many times.
+// Note that a tree built from data, like with [Map], is only static until the data changes.
+// If n is a [Group], its attributes and elements are kept apart, like with [StaticNode].
+func CompileNode(n Node) Node {
+	if n == nil {
+		return nil
+	}
+
+	if g, ok := n.(Group); ok {
+		return Group{compileGroup(g, AttributeType), compileGroup(g, ElementType)}
+	}
+
+	var c compiler
+	c.node(n)
+	c.flush()
+
+	return compiledNode{chunks: c.chunks, typ: nodeType(n)}
+}
+
+// staticGroup renders the children of g of the desired type once, like they're rendered in an element.
+// If they can't be rendered, they're compiled with [compileGroup] instead, so the error is returned on render.
+func staticGroup(g Group, desiredType NodeType) Node {
+	var b bytes.Buffer
+	for _, child := range g {
+		if err := renderChild(&b, child, desiredType); err != nil {
+			return compileGroup(g, desiredType)
+		}
+	}
+	if b.Len() == 0 {
+		return nil
+	}
+	return compiledNode{chunks: []compiledChunk{{b: b.Bytes()}}, typ: desiredType}
+}
+
+// compileGroup compiles the children of g of the desired type, like they're rendered in an element.
+func compileGroup(g Group, desiredType NodeType) Node {
+	var c compiler
+	for _, child := range g {
+		c.child(child, desiredType)
+	}
+	c.flush()
+
+	if len(c.chunks) == 0 {
+		return nil
+	}
+	return compiledNode{chunks: c.chunks, typ: desiredType}
+}
+
+// compiledChunk is either static bytes b, or a dynamic [Node] n that is rendered every time.
+type compiledChunk struct {
+	b []byte
+	n Node
+}
+
+// Compile-time check that [compiledNode] implements [fmt.Stringer], [Node], [ContextRenderer] and [nodeTypeDescriber].
+var _ interface {
+	fmt.Stringer
+	Node
+	ContextRenderer
+	nodeTypeDescriber
+} = compiledNode{}
+
+// compiledNode is a [Node] of chunks, as created by [StaticNode] and [CompileNode].
+type compiledNode struct {
+	chunks []compiledChunk
+	typ    NodeType
+}
+
+// Render satisfies [Node].
+func (c compiledNode) Render(w io.Writer) error {
+	return c.RenderContext(context.Background(), w)
+}
+
+// RenderContext satisfies [ContextRenderer].
+func (c compiledNode) RenderContext(ctx context.Context, w io.Writer) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	for _, chunk := range c.chunks {
+		if chunk.n != nil {
+			if err := renderWithContext(ctx, w, chunk.n); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, err := w.Write(chunk.b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Type satisfies [nodeTypeDescriber].
+func (c compiledNode) Type() NodeType {
+	return c.typ
+}
+
+// String satisfies [fmt.Stringer].
+func (c compiledNode) String() string {
+	var b strings.Builder
+	_ = c.Render(&b)
+	return b.String()
+}
+
+// compiler splits a [Node] tree into chunks of static bytes and dynamic nodes.
+type compiler struct {
+	chunks []compiledChunk
+	b      bytes.Buffer
+}
+
+// node n, rendering it into the current static bytes if possible.
+func (c *compiler) node(n Node) {
+	switch n := n.(type) {
+	case ElementNode:
+		c.element(n)
+	case AttributeNode, TextNode, RawNode, TrustedScript:
+		_ = n.Render(&c.b) // Writing to a bytes.Buffer can't fail
+	default:
+		c.dynamic(n)
+	}
+}
+
+// element e, like [ElementNode] renders it.
+func (c *compiler) element(e ElementNode) {
+	if e.Name == "script" || e.Name == "style" || (e.Name == "link" && isStylesheetLink(e)) {
+		c.dynamic(e)
+		return
+	}
+
+	c.b.WriteString("<" + e.Name)
+	for _, child := range e.Children {
+		c.child(child, AttributeType)
+	}
+	c.b.WriteString(">")
+
+	if isVoidElement(e.Name) {
+		return
+	}
+
+	for _, child := range e.Children {
+		c.child(child, ElementType)
+	}
+	c.b.WriteString("</" + e.Name + ">")
+}
+
+// child n of an element or a [Group], if it's of the desired type, like renderChild.
+func (c *compiler) child(n Node, desiredType NodeType) {
+	if n == nil {
+		return
+	}
+
+	if g, ok := n.(Group); ok {
+		for _, child := range g {
+			c.child(child, desiredType)
+		}
+		return
+	}
+
+	d, ok := n.(nodeTypeDescriber)
+	switch desiredType {
+	case ElementType:
+		if !ok || d.Type() == ElementType {
+			c.node(n)
+		}
+	case AttributeType:
+		if ok && d.Type() == AttributeType {
+			c.node(n)
+		}
+	}
+}
+
+// dynamic [Node] n, which is rendered every time.
+func (c *compiler) dynamic(n Node) {
+	c.flush()
+	c.chunks = append(c.chunks, compiledChunk{n: n})
+}
+
+// flush the current static bytes to a chunk.
+func (c *compiler) flush() {
+	if c.b.Len() == 0 {
+		return
+	}
+	c.chunks = append(c.chunks, compiledChunk{b: append([]byte(nil), c.b.Bytes()...)})
+	c.b.Reset()
+}
+
+// nodeType of n, which is [ElementType] if n doesn't describe its type.
+func nodeType(n Node) NodeType {
+	if d, ok := n.(nodeTypeDescriber); ok {
+		return d.Type()
+	}
+	return ElementType
+}
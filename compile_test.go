@@ -0,0 +1,169 @@
+package gomponents_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	g "maragu.dev/gomponents"
+	"maragu.dev/gomponents/internal/assert"
+)
+
+func TestStaticNode(t *testing.T) {
+	t.Run("renders the node only once", func(t *testing.T) {
+		var calls int
+		n := g.StaticNode(g.El("div", g.Attr("class", "hat"), g.NodeFunc(func(w io.Writer) error {
+			calls++
+			_, err := io.WriteString(w, "party")
+			return err
+		})))
+		assert.Equal(t, `<div class="hat">party</div>`, n)
+		assert.Equal(t, `<div class="hat">party</div>`, n)
+		if calls != 1 {
+			t.Fatal("unexpected number of calls", calls)
+		}
+	})
+
+	t.Run("keeps the node type", func(t *testing.T) {
+		assert.Equal(t, `<div class="hat"></div>`, g.El("div", g.StaticNode(g.Attr("class", "hat"))))
+	})
+
+	t.Run("keeps the attributes of a group", func(t *testing.T) {
+		n := g.StaticNode(g.Group{g.Attr("class", "hat"), g.El("span")})
+		assert.Equal(t, `<div class="hat"><span></span></div>`, g.El("div", n))
+		assert.Equal(t, `<span></span>`, n)
+	})
+
+	t.Run("returns render error on render in a group", func(t *testing.T) {
+		n := g.StaticNode(g.Group{g.El("span"), g.NodeFunc(func(io.Writer) error {
+			return errors.New("no party")
+		})})
+		err := n.Render(&strings.Builder{})
+		assert.Error(t, err)
+	})
+
+	t.Run("returns nil on nil node", func(t *testing.T) {
+		if g.StaticNode(nil) != nil {
+			t.FailNow()
+		}
+	})
+
+	t.Run("returns render error on render", func(t *testing.T) {
+		n := g.StaticNode(g.NodeFunc(func(io.Writer) error {
+			return errors.New("no party")
+		}))
+		err := n.Render(&strings.Builder{})
+		assert.Error(t, err)
+	})
+
+	t.Run("returns render error on cannot write", func(t *testing.T) {
+		err := g.StaticNode(g.El("div")).Render(&erroringWriter{})
+		assert.Error(t, err)
+	})
+}
+
+func TestCompileNode(t *testing.T) {
+	tests := []struct {
+		name string
+		node g.Node
+	}{
+		{name: "element", node: g.El("div", g.Attr("class", "hat"), g.Text("<party>"), g.Raw("<b>raw</b>"), g.TrustedScript("x"))},
+		{name: "nested elements and groups", node: g.El("div", g.Group{g.Attr("id", "a"), g.El("p", g.Group{nil, g.Text("party")})}, nil, g.El("br", g.Text("ignored")))},
+		{name: "dynamic nodes", node: g.El("div", outsider{}, customAttr{}, g.El("span", outsider{}))},
+		{name: "group", node: g.Group{g.El("p"), g.Attr("ignored"), outsider{}, g.Text("hat")}},
+		{name: "attribute", node: g.Attr("class", "hat")},
+		{name: "text", node: g.Text("hat")},
+		{name: "opaque node", node: outsider{}},
+		{name: "script, style, and stylesheet link", node: g.El("head", g.El("script"), g.El("style"), g.El("link", g.Attr("rel", "stylesheet")), g.El("link", g.Attr("rel", "icon")))},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var expected strings.Builder
+			_ = test.node.Render(&expected)
+			assert.Equal(t, expected.String(), g.CompileNode(test.node))
+		})
+	}
+
+	t.Run("renders dynamic nodes every time", func(t *testing.T) {
+		var calls int
+		n := g.CompileNode(g.El("div", g.NodeFunc(func(w io.Writer) error {
+			calls++
+			_, err := fmt.Fprint(w, calls)
+			return err
+		})))
+		assert.Equal(t, `<div>1</div>`, n)
+		assert.Equal(t, `<div>2</div>`, n)
+	})
+
+	t.Run("renders dynamic nodes with the context", func(t *testing.T) {
+		n := g.CompileNode(g.El("div", userName(), g.El("script")))
+
+		var b strings.Builder
+		ctx := g.WithCSPNonce(context.WithValue(context.Background(), contextKey("user"), "Bob"), "abc")
+		if err := g.RenderCtx(ctx, &b, n); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, `<div><span>Bob</span><script nonce="abc"></script></div>`, g.Raw(b.String()))
+	})
+
+	t.Run("keeps the attributes of a group", func(t *testing.T) {
+		n := g.CompileNode(g.Group{g.Attr("class", "hat"), g.El("span"), outsider{}})
+		assert.Equal(t, `<div class="hat"><span></span>outsider</div>`, g.El("div", n))
+		assert.Equal(t, `<span></span>outsider`, n)
+	})
+
+	t.Run("returns nil on nil node", func(t *testing.T) {
+		if g.CompileNode(nil) != nil {
+			t.FailNow()
+		}
+	})
+
+	t.Run("returns the context error if the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := g.RenderCtx(ctx, &strings.Builder{}, g.CompileNode(g.El("div")))
+		assert.Error(t, err)
+	})
+
+	t.Run("returns a string like the node", func(t *testing.T) {
+		n := g.CompileNode(g.El("div", outsider{}))
+		if s := n.(fmt.Stringer).String(); s != `<div>outsider</div>` {
+			t.Fatal("unexpected string", s)
+		}
+	})
+
+	t.Run("returns render error on cannot write", func(t *testing.T) {
+		n := g.CompileNode(g.El("div", outsider{}, g.El("span")))
+		for i := 0; i < 3; i++ {
+			t.Run(fmt.Sprintf("failing write %v", i), func(t *testing.T) {
+				err := n.Render(&erroringWriter{failingWrite: i})
+				assert.Error(t, err)
+			})
+		}
+	})
+
+	t.Run("returns render error from dynamic nodes", func(t *testing.T) {
+		n := g.CompileNode(g.El("div", g.NodeFunc(func(io.Writer) error {
+			return errors.New("no party")
+		})))
+		err := n.Render(&strings.Builder{})
+		assert.Error(t, err)
+	})
+}
+
+func ExampleCompileNode() {
+	layout := g.CompileNode(g.El("div", g.Attr("class", "layout"),
+		g.El("header", g.Text("Party hats")),
+		g.ContextFunc(func(ctx context.Context, w io.Writer) error {
+			return g.El("main", g.Text("Hats for everyone")).Render(w)
+		}),
+		g.El("footer", g.Text("© Party hats")),
+	))
+	_ = layout.Render(os.Stdout)
+	// Output: <div class="layout"><header>Party hats</header><main>Hats for everyone</main><footer>© Party hats</footer></div>
+}
@@ -23,6 +23,10 @@ type HTML5Props struct {
 }
 
 // HTML5 document template.
+// The CSS of [ScopedStyle] nodes in the body is rendered in a style element at the end of the head, see [CollectStyles].
+// The styles are found when HTML5 is called, not while the page renders, so styles in nodes that can't be inspected,
+// like [g.ContextFunc], cached and compiled nodes, and deferred content from the http package, are missing.
+// Add the CSS of those to Head yourself, for example with [CollectStyles] on the nodes they're in.
 func HTML5(p HTML5Props) g.Node {
 	return Doctype(
 		HTML(g.If(p.Language != "", Lang(p.Language)), p.HTMLAttrs,
@@ -32,6 +36,7 @@ func HTML5(p HTML5Props) g.Node {
 				TitleEl(g.Text(p.Title)),
 				g.If(p.Description != "", Meta(Name("description"), Content(p.Description))),
 				p.Head,
+				CollectStyles(p.Body),
 			),
 			Body(p.Body),
 		),
@@ -74,7 +79,7 @@ func (c Classes) String() string {
 // attribute is emitted.
 // When both boolean and valued attributes match, the valued form takes precedence.
 // The name is rendered unescaped and must be a trusted value, never user-controlled data.
-// Note that this renders all first-level attributes to check whether they should be processed.
+// Note that this renders all first-level attributes not created with [g.Attr] to check whether they should be processed.
 func JoinAttrs(name string, children ...g.Node) g.Node {
 	var attrValues []string
 	var result []g.Node
@@ -132,11 +137,25 @@ type nodeTypeDescriber interface {
 }
 
 func extractAttrValue(name string, n g.Node) (bool, string) {
+	// Attributes created with g.Attr can be inspected directly
+	if a, ok := n.(g.AttributeNode); ok {
+		if a.Name != name {
+			return false, ""
+		}
+		// Treat whitespace-only values the same as empty
+		if a.Boolean || strings.TrimSpace(a.Value) == "" {
+			return true, ""
+		}
+		return true, a.Value
+	}
+
 	// Ignore everything that is not an attribute
 	if n, ok := n.(nodeTypeDescriber); !ok || n.Type() == g.ElementType {
 		return false, ""
 	}
 
+	// Other attributes, like Classes, have to be rendered to find their name and value
+
 	var b strings.Builder
 	if err := n.Render(&b); err != nil {
 		return false, ""
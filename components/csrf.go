@@ -0,0 +1,60 @@
+package components
+
+import (
+	"context"
+	"io"
+
+	g "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/html"
+)
+
+// CSRFFieldName is the name of the form field with the CSRF token, and CSRFHeaderName the name of the request header,
+// for example for htmx requests.
+const (
+	CSRFFieldName  = "csrf_token"
+	CSRFHeaderName = "X-CSRF-Token"
+)
+
+type csrfTokenContextKey struct{}
+
+// WithCSRFToken returns a copy of ctx with the given CSRF token, for [CSRFField] and [CSRFMeta].
+// See the http package for middleware that issues and verifies tokens.
+func WithCSRFToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, csrfTokenContextKey{}, token)
+}
+
+// CSRFToken returns the CSRF token in ctx, as set by [WithCSRFToken], or an empty string if there is none.
+func CSRFToken(ctx context.Context) string {
+	token, _ := ctx.Value(csrfTokenContextKey{}).(string)
+	return token
+}
+
+// CSRFField returns a hidden input element with the CSRF token for the current request, for forms with unsafe methods
+// like POST. The token is from the context the node is rendered with, see [CSRFToken],
+// which is set by the CSRF middleware in the http package. Without a token, it renders nothing.
+func CSRFField() g.Node {
+	return g.ContextFunc(func(ctx context.Context, w io.Writer) error {
+		token := CSRFToken(ctx)
+		if token == "" {
+			return nil
+		}
+		return Input(Type("hidden"), Name(CSRFFieldName), Value(token)).Render(w)
+	})
+}
+
+// CSRFMeta returns a meta element named "csrf-token" with the CSRF token for the current request, like [CSRFField],
+// for requests made from JavaScript, which send the token in the X-CSRF-Token header.
+// For htmx, add the header to all requests with a listener in the page:
+//
+//	document.addEventListener("htmx:configRequest", (e) => {
+//		e.detail.headers["X-CSRF-Token"] = document.querySelector('meta[name="csrf-token"]').content;
+//	});
+func CSRFMeta() g.Node {
+	return g.ContextFunc(func(ctx context.Context, w io.Writer) error {
+		token := CSRFToken(ctx)
+		if token == "" {
+			return nil
+		}
+		return Meta(Name("csrf-token"), Content(token)).Render(w)
+	})
+}
@@ -0,0 +1,58 @@
+package components_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	g "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/components"
+	"maragu.dev/gomponents/internal/assert"
+)
+
+func TestCSRFToken(t *testing.T) {
+	t.Run("returns the token from the context", func(t *testing.T) {
+		ctx := WithCSRFToken(context.Background(), "abc")
+		if token := CSRFToken(ctx); token != "abc" {
+			t.Fatal("unexpected token", token)
+		}
+	})
+
+	t.Run("returns an empty string without a token", func(t *testing.T) {
+		if token := CSRFToken(context.Background()); token != "" {
+			t.Fatal("unexpected token", token)
+		}
+	})
+}
+
+func TestCSRFField(t *testing.T) {
+	t.Run("renders a hidden input with the token from the context", func(t *testing.T) {
+		var b strings.Builder
+		if err := g.RenderCtx(WithCSRFToken(context.Background(), "abc"), &b, CSRFField()); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != `<input type="hidden" name="csrf_token" value="abc">` {
+			t.Fatal("unexpected output", b.String())
+		}
+	})
+
+	t.Run("renders nothing without a token", func(t *testing.T) {
+		assert.Equal(t, "", CSRFField())
+	})
+}
+
+func TestCSRFMeta(t *testing.T) {
+	t.Run("renders a meta element with the token from the context", func(t *testing.T) {
+		var b strings.Builder
+		if err := g.RenderCtx(WithCSRFToken(context.Background(), "abc"), &b, CSRFMeta()); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != `<meta name="csrf-token" content="abc">` {
+			t.Fatal("unexpected output", b.String())
+		}
+	})
+
+	t.Run("renders nothing without a token", func(t *testing.T) {
+		assert.Equal(t, "", CSRFMeta())
+	})
+}
@@ -0,0 +1,126 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	g "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/html"
+)
+
+// CustomElementProps for [DefineCustomElement].
+type CustomElementProps struct {
+	// Name of the custom element, like "party-hat". See [ValidateCustomElementName] for the rules.
+	Name string
+
+	// ObservedAttributes of the custom element, which dispatch an "attributechanged" event when they change.
+	// See [CustomElement.Script].
+	ObservedAttributes []string
+
+	// Style is CSS for the shadow root, so it only applies to the template and doesn't leak out of the element.
+	// It's rendered unescaped and must be trusted, never user-controlled data.
+	Style string
+
+	// Template is rendered in the shadow root of every element.
+	// Use slot elements to place the children of the element, named with the name attribute
+	// and selected with the slot attribute, see [maragu.dev/gomponents/html.SlotEl] and [maragu.dev/gomponents/html.SlotAttr].
+	Template g.Node
+}
+
+// CustomElement definition, created with [DefineCustomElement].
+type CustomElement struct {
+	p CustomElementProps
+}
+
+// DefineCustomElement from the props, for server-rendered web components using declarative shadow DOM,
+// which browsers attach without any JavaScript.
+// It panics if the name is not a valid custom element name, so define elements at package level
+// or validate dynamic names with [ValidateCustomElementName] first.
+//
+// See https://developer.mozilla.org/en-US/docs/Web/API/Web_components/Using_shadow_DOM#declaratively_with_html
+func DefineCustomElement(p CustomElementProps) CustomElement {
+	if err := ValidateCustomElementName(p.Name); err != nil {
+		panic(err)
+	}
+	return CustomElement{p: p}
+}
+
+// Name of the custom element.
+func (c CustomElement) Name() string {
+	return c.p.Name
+}
+
+// El returns an element with the children, with the template and style rendered in
+// a <template shadowrootmode="open"> element first.
+// Attribute children are set on the element itself.
+func (c CustomElement) El(children ...g.Node) g.Node {
+	return g.El(c.p.Name,
+		Template(g.Attr("shadowrootmode", "open"),
+			g.If(c.p.Style != "", StyleEl(g.Raw(c.p.Style))),
+			c.p.Template,
+		),
+		g.Group(children),
+	)
+}
+
+// Script returns a script element that registers the custom element with the browser,
+// so it matches the :defined CSS pseudo-class and dispatches a bubbling "attributechanged" [CustomEvent]
+// with the name, oldValue, and newValue in its detail when one of the observed attributes changes.
+// It's optional, the elements render without it. Render it once per page, for example in the head.
+//
+// [CustomEvent]: https://developer.mozilla.org/en-US/docs/Web/API/CustomEvent
+func (c CustomElement) Script() g.Node {
+	observed := make([]string, len(c.p.ObservedAttributes))
+	for i, name := range c.p.ObservedAttributes {
+		observed[i] = string(g.JSString(name))
+	}
+
+	name := string(g.JSString(c.p.Name))
+	return Script(g.TrustedScript(
+		"if (!customElements.get(" + name + ")) {\n" +
+			"  customElements.define(" + name + ", class extends HTMLElement {\n" +
+			"    static observedAttributes = [" + strings.Join(observed, ", ") + "];\n" +
+			"    attributeChangedCallback(name, oldValue, newValue) {\n" +
+			"      this.dispatchEvent(new CustomEvent(\"attributechanged\", {bubbles: true, detail: {name, oldValue, newValue}}));\n" +
+			"    }\n" +
+			"  });\n" +
+			"}"))
+}
+
+// ValidateCustomElementName returns an error if the name is not a valid custom element name.
+// Valid names start with a lowercase ASCII letter, contain a hyphen, contain no uppercase ASCII letters,
+// and aren't one of the reserved names from SVG and MathML, like "font-face".
+//
+// See https://html.spec.whatwg.org/multipage/custom-elements.html#valid-custom-element-name
+func ValidateCustomElementName(name string) error {
+	if name == "" || name[0] < 'a' || name[0] > 'z' {
+		return fmt.Errorf("invalid custom element name %q: must start with a lowercase ASCII letter", name)
+	}
+	if !strings.Contains(name, "-") {
+		return fmt.Errorf("invalid custom element name %q: must contain a hyphen", name)
+	}
+	for _, r := range name {
+		if !isPotentialCustomElementNameChar(r) {
+			return fmt.Errorf("invalid custom element name %q: cannot contain %q", name, r)
+		}
+	}
+	switch name {
+	case "annotation-xml", "color-profile", "font-face", "font-face-src", "font-face-uri", "font-face-format",
+		"font-face-name", "missing-glyph":
+		return fmt.Errorf("invalid custom element name %q: reserved name", name)
+	}
+	return nil
+}
+
+// isPotentialCustomElementNameChar reports whether r is a PCENChar from the HTML spec.
+func isPotentialCustomElementNameChar(r rune) bool {
+	switch {
+	case r == '-', r == '.', r == '_', r >= '0' && r <= '9', r >= 'a' && r <= 'z', r == 0xB7,
+		r >= 0xC0 && r <= 0xD6, r >= 0xD8 && r <= 0xF6, r >= 0xF8 && r <= 0x37D, r >= 0x37F && r <= 0x1FFF,
+		r >= 0x200C && r <= 0x200D, r >= 0x203F && r <= 0x2040, r >= 0x2070 && r <= 0x218F,
+		r >= 0x2C00 && r <= 0x2FEF, r >= 0x3001 && r <= 0xD7FF, r >= 0xF900 && r <= 0xFDCF,
+		r >= 0xFDF0 && r <= 0xFFFD, r >= 0x10000 && r <= 0xEFFFF:
+		return true
+	}
+	return false
+}
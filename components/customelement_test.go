@@ -0,0 +1,114 @@
+package components_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	g "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/components"
+	. "maragu.dev/gomponents/html"
+	"maragu.dev/gomponents/internal/assert"
+)
+
+func TestDefineCustomElement(t *testing.T) {
+	partyHat := DefineCustomElement(CustomElementProps{
+		Name:               "party-hat",
+		ObservedAttributes: []string{"color", "size"},
+		Style:              ":host > p { color: red; }",
+		Template:           P(SlotEl(Name("title")), SlotEl()),
+	})
+
+	t.Run("renders the template and style in a declarative shadow root", func(t *testing.T) {
+		n := partyHat.El(Class("hat"), Span(SlotAttr("title"), g.Text("Party")), g.Text("hat"))
+		assert.Equal(t, `<party-hat class="hat"><template shadowrootmode="open"><style>:host > p { color: red; }</style>`+
+			`<p><slot name="title"></slot><slot></slot></p></template><span slot="title">Party</span>hat</party-hat>`, n)
+	})
+
+	t.Run("renders no style element without a style", func(t *testing.T) {
+		n := DefineCustomElement(CustomElementProps{Name: "party-hat"}).El()
+		assert.Equal(t, `<party-hat><template shadowrootmode="open"></template></party-hat>`, n)
+	})
+
+	t.Run("returns the name", func(t *testing.T) {
+		if partyHat.Name() != "party-hat" {
+			t.Fatal("unexpected name", partyHat.Name())
+		}
+	})
+
+	t.Run("returns a script defining the element with observed attributes", func(t *testing.T) {
+		expected := `<script>if (!customElements.get("party-hat")) {
+  customElements.define("party-hat", class extends HTMLElement {
+    static observedAttributes = ["color", "size"];
+    attributeChangedCallback(name, oldValue, newValue) {
+      this.dispatchEvent(new CustomEvent("attributechanged", {bubbles: true, detail: {name, oldValue, newValue}}));
+    }
+  });
+}</script>`
+		assert.Equal(t, expected, partyHat.Script())
+	})
+
+	t.Run("panics on invalid names", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("did not panic")
+			}
+		}()
+		DefineCustomElement(CustomElementProps{Name: "hat"})
+	})
+}
+
+func TestValidateCustomElementName(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{name: "party-hat", valid: true},
+		{name: "party-hat-2", valid: true},
+		{name: "x-", valid: true},
+		{name: "hat-ü.fun_", valid: true},
+		{name: "hat-😀", valid: true},
+		{name: ""},
+		{name: "hat"},
+		{name: "Party-hat"},
+		{name: "party-Hat"},
+		{name: "-hat"},
+		{name: "1-hat"},
+		{name: "party hat-"},
+		{name: "party-hat>"},
+		{name: "font-face"},
+		{name: "annotation-xml"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateCustomElementName(test.name)
+			if test.valid && err != nil {
+				t.Fatal("unexpected error", err)
+			}
+			if !test.valid && err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+
+	t.Run("explains what's wrong", func(t *testing.T) {
+		err := ValidateCustomElementName("hat")
+		if err == nil || err.Error() != `invalid custom element name "hat": must contain a hyphen` {
+			t.Fatal("unexpected error", err)
+		}
+	})
+}
+
+func ExampleDefineCustomElement() {
+	card := DefineCustomElement(CustomElementProps{
+		Name:     "hat-card",
+		Style:    "h2 { margin: 0; }",
+		Template: Article(H2(SlotEl(Name("title"))), SlotEl()),
+	})
+
+	_ = card.El(ID("party"), Span(SlotAttr("title"), g.Text("Party hat")), g.Text("Wear it with pride.")).Render(os.Stdout)
+	fmt.Println()
+	// Output:
+	// <hat-card id="party"><template shadowrootmode="open"><style>h2 { margin: 0; }</style><article><h2><slot name="title"></slot></h2><slot></slot></article></template><span slot="title">Party hat</span>Wear it with pride.</hat-card>
+}
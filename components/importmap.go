@@ -0,0 +1,73 @@
+package components
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	g "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/html"
+)
+
+// ImportMap of JavaScript module specifiers to URLs, which lets browsers resolve bare imports like
+// import "htmx" without a JS build step. Render it with [ImportMap.Script] before any module scripts.
+//
+// See https://developer.mozilla.org/en-US/docs/Web/HTML/Element/script/type/importmap
+type ImportMap struct {
+	// Imports maps module specifiers to URLs, like "htmx" to "/assets/htmx.1a2b3c4d.js".
+	Imports map[string]string `json:"imports,omitempty"`
+
+	// Scopes maps URL prefixes to imports that are only used by modules with URLs starting with the prefix.
+	Scopes map[string]map[string]string `json:"scopes,omitempty"`
+
+	// Integrity maps module URLs to integrity values, like from [maragu.dev/gomponents/assets.Assets.Integrity].
+	Integrity map[string]string `json:"integrity,omitempty"`
+}
+
+// Script returns a script element with the import map as JSON.
+// The characters "<", ">", and "&" are escaped in the JSON, so values can't end the script element early,
+// even if they contain "</script>".
+func (m ImportMap) Script() g.Node {
+	b, _ := json.Marshal(m) // Marshalling maps of strings can't fail
+	return Script(Type("importmap"), g.Raw(string(b)))
+}
+
+// Preload returns link elements with rel="modulepreload" for the modules with the given specifiers in Imports,
+// with an integrity attribute if the module URL is in Integrity. Without specifiers, all modules are preloaded,
+// sorted by specifier, except for prefix mappings ending with "/".
+// Preloading modules makes the browser fetch them in parallel, instead of discovering the imports one by one.
+// It panics if a specifier isn't in Imports.
+func (m ImportMap) Preload(specifiers ...string) g.Node {
+	if len(specifiers) == 0 {
+		for specifier := range m.Imports {
+			if !strings.HasSuffix(specifier, "/") {
+				specifiers = append(specifiers, specifier)
+			}
+		}
+		sort.Strings(specifiers)
+	}
+
+	var links g.Group
+	for _, specifier := range specifiers {
+		url, ok := m.Imports[specifier]
+		if !ok {
+			panic(fmt.Sprintf("components: no import map entry for %q", specifier))
+		}
+		integrity, ok := m.Integrity[url]
+		links = append(links, Link(Rel("modulepreload"), Href(url), g.If(ok, Integrity(integrity))))
+	}
+	return links
+}
+
+// ModuleScript returns a script element with type="module" and the src URL, for a module entrypoint.
+// Other attributes can be added as children.
+func ModuleScript(src string, children ...g.Node) g.Node {
+	return Script(Type("module"), Src(src), g.Group(children))
+}
+
+// ImportModule returns an inline script element with type="module" that imports the module with the specifier,
+// which is resolved with the import map, like import "app".
+func ImportModule(specifier string) g.Node {
+	return Script(Type("module"), g.TrustedScript("import "+string(g.JSString(specifier))+";"))
+}
@@ -0,0 +1,86 @@
+package components_test
+
+import (
+	"os"
+	"testing"
+
+	g "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/components"
+	. "maragu.dev/gomponents/html"
+	"maragu.dev/gomponents/internal/assert"
+)
+
+func TestImportMap(t *testing.T) {
+	m := ImportMap{
+		Imports: map[string]string{
+			"party":  "/assets/party.1a2b3c4d.js",
+			"hat":    "https://example.com/hat.js",
+			"utils/": "/assets/utils/",
+		},
+		Scopes: map[string]map[string]string{
+			"/assets/utils/": {"hat": "/assets/utils/hat.js"},
+		},
+		Integrity: map[string]string{
+			"/assets/party.1a2b3c4d.js": "sha384-abc",
+		},
+	}
+
+	t.Run("renders a script element with the import map as JSON", func(t *testing.T) {
+		assert.Equal(t, `<script type="importmap">{"imports":{"hat":"https://example.com/hat.js","party":"/assets/party.1a2b3c4d.js","utils/":"/assets/utils/"},`+
+			`"scopes":{"/assets/utils/":{"hat":"/assets/utils/hat.js"}},"integrity":{"/assets/party.1a2b3c4d.js":"sha384-abc"}}</script>`, m.Script())
+	})
+
+	t.Run("omits empty fields", func(t *testing.T) {
+		assert.Equal(t, `<script type="importmap">{"imports":{"hat":"/hat.js"}}</script>`, ImportMap{Imports: map[string]string{"hat": "/hat.js"}}.Script())
+	})
+
+	t.Run("escapes script end tags in the JSON", func(t *testing.T) {
+		m := ImportMap{Imports: map[string]string{"</script><script>alert(1)</script>": "/hat.js?a=1&b=<2>"}}
+		assert.Equal(t, `<script type="importmap">{"imports":{"\u003c/script\u003e\u003cscript\u003ealert(1)\u003c/script\u003e":"/hat.js?a=1\u0026b=\u003c2\u003e"}}</script>`, m.Script())
+	})
+
+	t.Run("renders modulepreload links for all modules without specifiers", func(t *testing.T) {
+		assert.Equal(t, `<link rel="modulepreload" href="https://example.com/hat.js">`+
+			`<link rel="modulepreload" href="/assets/party.1a2b3c4d.js" integrity="sha384-abc">`, m.Preload())
+	})
+
+	t.Run("renders modulepreload links for the given specifiers", func(t *testing.T) {
+		assert.Equal(t, `<link rel="modulepreload" href="/assets/party.1a2b3c4d.js" integrity="sha384-abc">`, m.Preload("party"))
+	})
+
+	t.Run("panics on unknown specifiers", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r != `components: no import map entry for "cake"` {
+				t.Fatal("unexpected panic", r)
+			}
+		}()
+		m.Preload("cake")
+	})
+}
+
+func TestModuleScript(t *testing.T) {
+	t.Run("renders a module script element with the src", func(t *testing.T) {
+		assert.Equal(t, `<script type="module" src="/app.js" async></script>`, ModuleScript("/app.js", Async()))
+	})
+}
+
+func TestImportModule(t *testing.T) {
+	t.Run("renders an inline module script importing the specifier", func(t *testing.T) {
+		assert.Equal(t, `<script type="module">import "party";</script>`, ImportModule("party"))
+	})
+
+	t.Run("escapes the specifier", func(t *testing.T) {
+		assert.Equal(t, `<script type="module">import "\u003c/script\u003e\"";</script>`, ImportModule(`</script>"`))
+	})
+}
+
+func ExampleImportMap() {
+	m := ImportMap{Imports: map[string]string{
+		"app":  "/assets/app.1a2b3c4d.js",
+		"htmx": "/assets/htmx.5e6f7a8b.js",
+	}}
+
+	_ = g.Group{m.Script(), m.Preload(), ImportModule("app")}.Render(os.Stdout)
+	// Output:
+	// <script type="importmap">{"imports":{"app":"/assets/app.1a2b3c4d.js","htmx":"/assets/htmx.5e6f7a8b.js"}}</script><link rel="modulepreload" href="/assets/app.1a2b3c4d.js"><link rel="modulepreload" href="/assets/htmx.5e6f7a8b.js"><script type="module">import "app";</script>
+}
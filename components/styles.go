@@ -0,0 +1,95 @@
+package components
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io"
+	"strings"
+
+	g "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/html"
+)
+
+// Compile-time check that [ScopedStyle] implements [fmt.Stringer], [g.Node], and [nodeTypeDescriber].
+var _ interface {
+	fmt.Stringer
+	g.Node
+	nodeTypeDescriber
+} = ScopedStyle{}
+
+// ScopedStyle is CSS for a component, scoped to a class name derived from a hash of the CSS,
+// so the class name is stable between builds and only changes when the CSS does. Create it with [NewScopedStyle].
+// As a [g.Node], it Renders the class attribute, so add it to the root element of the component.
+// [HTML5] finds the scoped styles in the body and renders their CSS in the head, see [CollectStyles].
+type ScopedStyle struct {
+	class   string
+	css     string
+	classes []string
+}
+
+// NewScopedStyle from CSS, which is nested in a rule for the scope class.
+// Declarations apply to the element with the class, and nested rules like "& h2 { … }" or "h2 { … }"
+// to its descendants. See https://developer.mozilla.org/en-US/docs/Web/CSS/CSS_nesting
+// The CSS is rendered unescaped and must be trusted, never user-controlled data.
+// Create scoped styles once at package level, next to the component using them.
+func NewScopedStyle(css string) ScopedStyle {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(css)) // Writing to a hash never fails
+	return ScopedStyle{class: fmt.Sprintf("css-%08x", h.Sum32()), css: css}
+}
+
+// Class name of the scope, like "css-1a2b3c4d".
+func (s ScopedStyle) Class() string {
+	return s.class
+}
+
+// CSS of the style, nested in a rule for the scope class.
+func (s ScopedStyle) CSS() string {
+	return "." + s.class + " {" + s.css + "}"
+}
+
+// With returns a copy of the scoped style which also renders the given classes in the class attribute,
+// because an element can only have one class attribute.
+func (s ScopedStyle) With(classes ...string) ScopedStyle {
+	s.classes = append(append([]string(nil), s.classes...), classes...)
+	return s
+}
+
+// Render satisfies [g.Node].
+func (s ScopedStyle) Render(w io.Writer) error {
+	return Class(strings.Join(append([]string{s.class}, s.classes...), " ")).Render(w)
+}
+
+// Type satisfies [nodeTypeDescriber].
+func (s ScopedStyle) Type() g.NodeType {
+	return g.AttributeType
+}
+
+// String satisfies [fmt.Stringer].
+func (s ScopedStyle) String() string {
+	var b strings.Builder
+	_ = s.Render(&b)
+	return b.String()
+}
+
+// CollectStyles returns a style element with the CSS of all [ScopedStyle] nodes in the tree rooted at n,
+// in the order they're first used, or nil if there are none.
+// The tree is walked when CollectStyles is called, so scoped styles in nodes that can't be inspected are not found,
+// see [g.Walk]. That includes [g.NodeFunc], [g.ContextFunc], nodes from [g.CacheNode], [g.CompileNode],
+// and [g.StaticNode], and deferred content from the http package. There's no warning, the CSS is just missing,
+// so add those styles to the tree where they're used, or render their CSS yourself.
+func CollectStyles(n g.Node) g.Node {
+	var css []string
+	seen := map[string]bool{}
+	g.Inspect(n, func(n g.Node) bool {
+		if s, ok := n.(ScopedStyle); ok && !seen[s.class] {
+			seen[s.class] = true
+			css = append(css, s.CSS())
+		}
+		return true
+	})
+	if len(css) == 0 {
+		return nil
+	}
+	return StyleEl(g.Raw(strings.Join(css, "\n")))
+}
@@ -0,0 +1,88 @@
+package components_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	g "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/components"
+	. "maragu.dev/gomponents/html"
+	ghttp "maragu.dev/gomponents/http"
+	"maragu.dev/gomponents/internal/assert"
+)
+
+var (
+	cardStyle  = NewScopedStyle("padding: 1rem; h2 { margin: 0; }")
+	titleStyle = NewScopedStyle("font-weight: bold;")
+)
+
+func TestScopedStyle(t *testing.T) {
+	t.Run("renders a class attribute with a stable hashed class name", func(t *testing.T) {
+		assert.Equal(t, `<div class="css-e88ce945"></div>`, Div(cardStyle))
+		if cardStyle.Class() != NewScopedStyle("padding: 1rem; h2 { margin: 0; }").Class() {
+			t.Fatal("class names differ for the same CSS")
+		}
+		if cardStyle.Class() == titleStyle.Class() {
+			t.Fatal("class names are the same for different CSS")
+		}
+	})
+
+	t.Run("renders other classes with the scope class", func(t *testing.T) {
+		s := cardStyle.With("party").With("hat")
+		assert.Equal(t, `<div class="css-e88ce945 party hat"></div>`, Div(s))
+		assert.Equal(t, `<div class="css-e88ce945"></div>`, Div(cardStyle))
+	})
+
+	t.Run("nests the CSS in a rule for the scope class", func(t *testing.T) {
+		if css := cardStyle.CSS(); css != ".css-e88ce945 {padding: 1rem; h2 { margin: 0; }}" {
+			t.Fatal("unexpected CSS", css)
+		}
+	})
+
+	t.Run("also works with fmt", func(t *testing.T) {
+		if s := fmt.Sprint(titleStyle); s != ` class="`+titleStyle.Class()+`"` {
+			t.Fatal("unexpected string", s)
+		}
+	})
+}
+
+func TestCollectStyles(t *testing.T) {
+	t.Run("collects styles used in the tree once, in order of first use", func(t *testing.T) {
+		n := Div(cardStyle, H2(titleStyle.With("big")), Div(g.Group{cardStyle}), g.If(false, Div(NewScopedStyle("color: red;"))))
+		assert.Equal(t, "<style>"+cardStyle.CSS()+"\n"+titleStyle.CSS()+"</style>", CollectStyles(n))
+	})
+
+	t.Run("returns nil without styles", func(t *testing.T) {
+		if n := CollectStyles(Div(Class("hat"))); n != nil {
+			t.Fatal("unexpected node", n)
+		}
+	})
+
+	t.Run("renders styles used in the body in the head of the HTML5 document", func(t *testing.T) {
+		n := HTML5(HTML5Props{Title: "Hat", Body: g.Group{Div(cardStyle, H2(titleStyle))}})
+		assert.Equal(t, `<!doctype html><html><head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"><title>Hat</title>`+
+			"<style>"+cardStyle.CSS()+"\n"+titleStyle.CSS()+"</style></head>"+
+			`<body><div class="`+cardStyle.Class()+`"><h2 class="`+titleStyle.Class()+`"></h2></div></body></html>`, n)
+	})
+
+	t.Run("misses styles in deferred content, which can't be inspected", func(t *testing.T) {
+		n := HTML5(HTML5Props{Title: "Hat", Body: g.Group{ghttp.Deferred(g.Text("Loading…"), func(ctx context.Context) g.Node {
+			return Div(cardStyle)
+		})}})
+		assert.Equal(t, `<!doctype html><html><head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"><title>Hat</title></head>`+
+			`<body><div class="`+cardStyle.Class()+`"></div></body></html>`, n)
+	})
+}
+
+func ExampleNewScopedStyle() {
+	partyStyle := NewScopedStyle("color: hotpink; strong { font-size: 2em; }")
+	party := func() g.Node {
+		return P(partyStyle, Strong(g.Text("Party")), g.Text(" hat"))
+	}
+
+	_ = HTML5(HTML5Props{Title: "Party", Body: g.Group{party()}}).Render(os.Stdout)
+	// Output:
+	// <!doctype html><html><head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"><title>Party</title><style>.css-4e7121f1 {color: hotpink; strong { font-size: 2em; }}</style></head><body><p class="css-4e7121f1"><strong>Party</strong> hat</p></body></html>
+}
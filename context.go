@@ -0,0 +1,65 @@
+package gomponents
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ContextRenderer can be implemented by Nodes that need a [context.Context] when rendering,
+// for example to get request-scoped values like the current user or a CSP nonce.
+// See [RenderCtx] and [ContextFunc].
+type ContextRenderer interface {
+	RenderContext(ctx context.Context, w io.Writer) error
+}
+
+// RenderCtx renders the [Node] n to the writer w with the context ctx.
+// If n implements [ContextRenderer], it's rendered with RenderContext, otherwise with Render.
+// [ElementNode] and [Group] pass the context on to children that implement [ContextRenderer],
+// and stop rendering with the context error once the context is done.
+func RenderCtx(ctx context.Context, w io.Writer, n Node) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if n == nil {
+		return nil
+	}
+	return renderWithContext(ctx, w, n)
+}
+
+// Compile-time check that [ContextFunc] implements [fmt.Stringer], [Node], [ContextRenderer] and [nodeTypeDescriber].
+var _ interface {
+	fmt.Stringer
+	Node
+	ContextRenderer
+	nodeTypeDescriber
+} = (ContextFunc)(nil)
+
+// ContextFunc is a render function with a [context.Context] that is also a [Node] of [ElementType].
+// It's like [NodeFunc], but gets the context given to [RenderCtx].
+// When rendered without a context, it gets [context.Background].
+// Use [RenderCtx] to render child nodes with the context.
+type ContextFunc func(context.Context, io.Writer) error
+
+// Render satisfies [Node].
+func (f ContextFunc) Render(w io.Writer) error {
+	return f(context.Background(), w)
+}
+
+// RenderContext satisfies [ContextRenderer].
+func (f ContextFunc) RenderContext(ctx context.Context, w io.Writer) error {
+	return f(ctx, w)
+}
+
+// Type satisfies [nodeTypeDescriber].
+func (ContextFunc) Type() NodeType {
+	return ElementType
+}
+
+// String satisfies [fmt.Stringer].
+func (f ContextFunc) String() string {
+	var b strings.Builder
+	_ = f.Render(&b)
+	return b.String()
+}
@@ -0,0 +1,132 @@
+package gomponents_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	g "maragu.dev/gomponents"
+	"maragu.dev/gomponents/internal/assert"
+)
+
+type contextKey string
+
+// userName is a component that renders the user name from the context.
+func userName() g.Node {
+	return g.ContextFunc(func(ctx context.Context, w io.Writer) error {
+		name, _ := ctx.Value(contextKey("user")).(string)
+		return g.RenderCtx(ctx, w, g.El("span", g.Text(name)))
+	})
+}
+
+func TestRenderCtx(t *testing.T) {
+	t.Run("passes the context to context renderers through elements and groups", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), contextKey("user"), "Partyhat")
+		n := g.El("div", g.Group{g.El("p", userName())}, userName())
+
+		var b strings.Builder
+		if err := g.RenderCtx(ctx, &b, n); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != "<div><p><span>Partyhat</span></p><span>Partyhat</span></div>" {
+			t.Fatal("unexpected output", b.String())
+		}
+	})
+
+	t.Run("renders nodes that are not context renderers normally", func(t *testing.T) {
+		var b strings.Builder
+		if err := g.RenderCtx(context.Background(), &b, outsider{}); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != "outsider" {
+			t.Fatal("unexpected output", b.String())
+		}
+	})
+
+	t.Run("does nothing on nil node", func(t *testing.T) {
+		var b strings.Builder
+		if err := g.RenderCtx(context.Background(), &b, nil); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("returns the context error if the context is done before rendering", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var b strings.Builder
+		err := g.RenderCtx(ctx, &b, g.El("div"))
+		if !errors.Is(err, context.Canceled) {
+			t.Fatal("unexpected error", err)
+		}
+		if b.String() != "" {
+			t.Fatal("unexpected output", b.String())
+		}
+	})
+
+	t.Run("stops rendering elements when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancelling := g.ContextFunc(func(context.Context, io.Writer) error {
+			cancel()
+			return nil
+		})
+
+		var b strings.Builder
+		err := g.RenderCtx(ctx, &b, g.El("div", g.El("span"), cancelling, g.El("p"), g.El("p")))
+		if !errors.Is(err, context.Canceled) {
+			t.Fatal("unexpected error", err)
+		}
+		if b.String() != "<div><span></span>" {
+			t.Fatal("unexpected output", b.String())
+		}
+	})
+
+	t.Run("stops rendering groups when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		n := g.Group{
+			g.ContextFunc(func(ctx context.Context, w io.Writer) error {
+				cancel()
+				_, err := io.WriteString(w, "party")
+				return err
+			}),
+			g.ContextFunc(func(ctx context.Context, w io.Writer) error {
+				_, err := io.WriteString(w, "hat")
+				return err
+			}),
+		}
+
+		var b strings.Builder
+		err := g.RenderCtx(ctx, &b, n)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatal("unexpected error", err)
+		}
+		if b.String() != "party" {
+			t.Fatal("unexpected output", b.String())
+		}
+	})
+
+	t.Run("uses the background context when rendering without context", func(t *testing.T) {
+		assert.Equal(t, "<div><span></span></div>", g.El("div", userName()))
+	})
+}
+
+func TestContextFunc(t *testing.T) {
+	t.Run("is an element and implements fmt.Stringer", func(t *testing.T) {
+		f := g.ContextFunc(func(ctx context.Context, w io.Writer) error {
+			_, err := io.WriteString(w, "hat")
+			return err
+		})
+		if f.Type() != g.ElementType || f.String() != "hat" {
+			t.FailNow()
+		}
+	})
+}
+
+func ExampleRenderCtx() {
+	ctx := context.WithValue(context.Background(), contextKey("user"), "Partyhat")
+	_ = g.RenderCtx(ctx, os.Stdout, g.El("div", g.Text("Hello, "), userName()))
+	// Output: <div>Hello, <span>Partyhat</span></div>
+}
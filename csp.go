@@ -0,0 +1,56 @@
+package gomponents
+
+import (
+	"context"
+	"strings"
+)
+
+type cspNonceContextKey struct{}
+
+// WithCSPNonce returns a copy of ctx with the given Content-Security-Policy nonce.
+// When rendered with the returned context, see [RenderCtx], script and style elements and stylesheet link elements
+// automatically get a nonce attribute with the nonce, unless they already have one.
+// See the http package for middleware that generates a nonce for each request and sets the matching header.
+func WithCSPNonce(ctx context.Context, nonce string) context.Context {
+	return context.WithValue(ctx, cspNonceContextKey{}, nonce)
+}
+
+// CSPNonce returns the Content-Security-Policy nonce in ctx, as set by [WithCSPNonce], or an empty string if there is none.
+func CSPNonce(ctx context.Context) string {
+	nonce, _ := ctx.Value(cspNonceContextKey{}).(string)
+	return nonce
+}
+
+// cspNonce returns the nonce to add to the element e when rendered with ctx,
+// or an empty string if the element doesn't need one.
+func cspNonce(ctx context.Context, e ElementNode) string {
+	if e.Name != "script" && e.Name != "style" && e.Name != "link" {
+		return ""
+	}
+
+	nonce := CSPNonce(ctx)
+	if nonce == "" {
+		return ""
+	}
+	if e.Name == "link" && !isStylesheetLink(e) {
+		return ""
+	}
+	if _, ok := e.Attribute("nonce"); ok {
+		return ""
+	}
+	return nonce
+}
+
+// isStylesheetLink reports whether the link element e has "stylesheet" among its rel values.
+func isStylesheetLink(e ElementNode) bool {
+	rel, ok := e.Attribute("rel")
+	if !ok {
+		return false
+	}
+	for _, v := range strings.Fields(rel.Value) {
+		if strings.EqualFold(v, "stylesheet") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package gomponents_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	g "maragu.dev/gomponents"
+	"maragu.dev/gomponents/internal/assert"
+)
+
+func TestCSPNonce(t *testing.T) {
+	t.Run("returns the nonce from the context", func(t *testing.T) {
+		ctx := g.WithCSPNonce(context.Background(), "abc")
+		if nonce := g.CSPNonce(ctx); nonce != "abc" {
+			t.Fatal("unexpected nonce", nonce)
+		}
+	})
+
+	t.Run("returns an empty string without a nonce", func(t *testing.T) {
+		if nonce := g.CSPNonce(context.Background()); nonce != "" {
+			t.Fatal("unexpected nonce", nonce)
+		}
+	})
+}
+
+func TestWithCSPNonce(t *testing.T) {
+	tests := []struct {
+		name     string
+		node     g.Node
+		expected string
+	}{
+		{
+			name:     "script",
+			node:     g.El("script", g.Attr("src", "/app.js")),
+			expected: `<script nonce="abc" src="/app.js"></script>`,
+		},
+		{
+			name:     "style",
+			node:     g.El("style", g.Raw("p{color:red}")),
+			expected: `<style nonce="abc">p{color:red}</style>`,
+		},
+		{
+			name:     "stylesheet link",
+			node:     g.El("link", g.Group{g.Attr("rel", "preload Stylesheet")}, g.Attr("href", "/app.css")),
+			expected: `<link nonce="abc" rel="preload Stylesheet" href="/app.css">`,
+		},
+		{
+			name:     "other link",
+			node:     g.El("link", g.Attr("rel", "icon"), g.Attr("href", "/favicon.ico")),
+			expected: `<link rel="icon" href="/favicon.ico">`,
+		},
+		{
+			name:     "link without rel",
+			node:     g.El("link", g.Attr("href", "/app.css")),
+			expected: `<link href="/app.css">`,
+		},
+		{
+			name:     "existing nonce",
+			node:     g.El("script", g.Attr("nonce", "xyz")),
+			expected: `<script nonce="xyz"></script>`,
+		},
+		{
+			name:     "nested elements",
+			node:     g.El("div", g.El("script"), g.El("p"), g.Group{g.El("style")}),
+			expected: `<div><script nonce="abc"></script><p></p><style nonce="abc"></style></div>`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var b strings.Builder
+			if err := g.RenderCtx(g.WithCSPNonce(context.Background(), "abc"), &b, test.node); err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != test.expected {
+				t.Fatalf("expected %v but got %v", test.expected, b.String())
+			}
+		})
+	}
+
+	t.Run("escapes the nonce", func(t *testing.T) {
+		var b strings.Builder
+		_ = g.RenderCtx(g.WithCSPNonce(context.Background(), `"a"`), &b, g.El("script"))
+		assert.Equal(t, `<script nonce="&#34;a&#34;"></script>`, g.Raw(b.String()))
+	})
+
+	t.Run("does not add a nonce without one in the context", func(t *testing.T) {
+		assert.Equal(t, `<script></script>`, g.El("script"))
+	})
+
+	t.Run("returns render error on cannot write", func(t *testing.T) {
+		ctx := g.WithCSPNonce(context.Background(), "abc")
+		for i := 0; i < 3; i++ {
+			t.Run(fmt.Sprintf("failing write %v", i), func(t *testing.T) {
+				err := g.RenderCtx(ctx, &erroringWriter{failingWrite: i}, g.El("script"))
+				assert.Error(t, err)
+			})
+		}
+	})
+}
+
+func ExampleWithCSPNonce() {
+	ctx := g.WithCSPNonce(context.Background(), "r4nd0m")
+	_ = g.RenderCtx(ctx, os.Stdout, g.El("script", g.Raw("alert('Party!')")))
+	// Output: <script nonce="r4nd0m">alert('Party!')</script>
+}
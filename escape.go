@@ -0,0 +1,113 @@
+package gomponents
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// unsafeURL replaces URLs with unsafe schemes, and unsafeCSS replaces unsafe CSS.
+// They're the same values html/template uses, so they're easy to search for when debugging.
+const (
+	unsafeURL = "#ZgotmplZ"
+	unsafeCSS = "ZgotmplZ"
+)
+
+// SafeURL is a URL that's trusted to be safe, so it's not sanitized by [SafeURLAttr].
+// Never convert user-controlled data to a SafeURL.
+type SafeURL string
+
+// URLAttr creates a name-value attribute DOM [Node] for a URL, like [Attr], but sanitizes the URL value first.
+// Relative URLs and URLs with the http, https, and mailto schemes are safe and kept as they are.
+// Other URLs, like "javascript:alert(1)" and data URLs, are replaced with "#ZgotmplZ", the same way html/template does.
+// Use [SafeURLAttr] for trusted URLs with other schemes.
+// The returned [Node] is an [AttributeNode], which can be inspected.
+func URLAttr(name, value string) Node {
+	if !isSafeURL(value) {
+		value = unsafeURL
+	}
+	return AttributeNode{Name: name, Value: value}
+}
+
+// SafeURLAttr creates a name-value attribute DOM [Node] for a trusted URL, which is escaped but not sanitized.
+// The returned [Node] is an [AttributeNode], which can be inspected.
+func SafeURLAttr(name string, value SafeURL) Node {
+	return AttributeNode{Name: name, Value: string(value)}
+}
+
+// isSafeURL reports whether the URL is relative or has a safe scheme.
+// See the urlFilter in html/template.
+func isSafeURL(s string) bool {
+	i := strings.IndexByte(s, ':')
+	if i < 0 || strings.ContainsRune(s[:i], '/') {
+		return true
+	}
+	switch strings.ToLower(s[:i]) {
+	case "http", "https", "mailto":
+		return true
+	}
+	return false
+}
+
+// CSSAttr creates a name-value attribute DOM [Node] for CSS declarations, like [Attr], but sanitizes the value first.
+// Values that can run scripts or hide what they do from the sanitizer, like expression(...), javascript: URLs,
+// backslash escapes, and comments, are replaced with "ZgotmplZ", the same way html/template does.
+// Use [Attr] directly for trusted CSS.
+// The returned [Node] is an [AttributeNode], which can be inspected.
+func CSSAttr(name, value string) Node {
+	if !isSafeCSS(value) {
+		value = unsafeCSS
+	}
+	return AttributeNode{Name: name, Value: value}
+}
+
+// isSafeCSS reports whether the CSS declarations can't run scripts or break out of the declarations.
+func isSafeCSS(s string) bool {
+	if strings.ContainsAny(s, "\\<>{}\x00") || strings.Contains(s, "/*") {
+		return false
+	}
+	s = strings.ToLower(s)
+	for _, unsafe := range []string{"expression", "javascript:", "vbscript:", "-moz-binding", "@import"} {
+		if strings.Contains(s, unsafe) {
+			return false
+		}
+	}
+	return true
+}
+
+// Compile-time check that [TrustedScript] implements [fmt.Stringer], [Node], and [nodeTypeDescriber].
+var _ interface {
+	fmt.Stringer
+	Node
+	nodeTypeDescriber
+} = TrustedScript("")
+
+// TrustedScript is JavaScript code that's trusted to be safe, for script elements and event handler attributes.
+// As a [Node], it Renders the unescaped script, like [RawNode].
+// Never convert user-controlled data to a TrustedScript, use [JSString] to embed it in the script instead.
+type TrustedScript string
+
+// Render satisfies [Node].
+func (s TrustedScript) Render(w io.Writer) error {
+	_, err := io.WriteString(w, string(s))
+	return err
+}
+
+// String satisfies [fmt.Stringer].
+func (s TrustedScript) String() string {
+	return string(s)
+}
+
+// Type satisfies [nodeTypeDescriber].
+func (TrustedScript) Type() NodeType {
+	return ElementType
+}
+
+// JSString returns s as a quoted JavaScript string literal, to embed user-controlled data in a [TrustedScript].
+// The characters "<", ">", "&", U+2028, and U+2029 are escaped as well,
+// so the literal is safe in both script elements and event handler attributes.
+func JSString(s string) TrustedScript {
+	b, _ := json.Marshal(s) // Marshalling a string can't fail
+	return TrustedScript(b)
+}
@@ -0,0 +1,121 @@
+package gomponents_test
+
+import (
+	"os"
+	"testing"
+
+	g "maragu.dev/gomponents"
+	"maragu.dev/gomponents/internal/assert"
+)
+
+func TestURLAttr(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected string
+	}{
+		{url: "", expected: ` href=""`},
+		{url: "/party?hat=yes&color=red", expected: ` href="/party?hat=yes&amp;color=red"`},
+		{url: "party/hat", expected: ` href="party/hat"`},
+		{url: "#hat", expected: ` href="#hat"`},
+		{url: "//www.gomponents.com", expected: ` href="//www.gomponents.com"`},
+		{url: "http://www.gomponents.com", expected: ` href="http://www.gomponents.com"`},
+		{url: "HTTPS://www.gomponents.com", expected: ` href="HTTPS://www.gomponents.com"`},
+		{url: "mailto:party@example.com", expected: ` href="mailto:party@example.com"`},
+		{url: "/party:hat", expected: ` href="/party:hat"`},
+		{url: "javascript:alert(1)", expected: ` href="#ZgotmplZ"`},
+		{url: "JavaScript:alert(1)", expected: ` href="#ZgotmplZ"`},
+		{url: " javascript:alert(1)", expected: ` href="#ZgotmplZ"`},
+		{url: "java\tscript:alert(1)", expected: ` href="#ZgotmplZ"`},
+		{url: "data:text/html,<script>alert(1)</script>", expected: ` href="#ZgotmplZ"`},
+		{url: "vbscript:msgbox(1)", expected: ` href="#ZgotmplZ"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.url, func(t *testing.T) {
+			assert.Equal(t, test.expected, g.URLAttr("href", test.url))
+		})
+	}
+
+	t.Run("returns an inspectable attribute node", func(t *testing.T) {
+		a, ok := g.URLAttr("href", "javascript:alert(1)").(g.AttributeNode)
+		if !ok || a.Name != "href" || a.Value != "#ZgotmplZ" || a.Boolean {
+			t.FailNow()
+		}
+	})
+}
+
+func TestSafeURLAttr(t *testing.T) {
+	t.Run("does not sanitize, but escapes the URL", func(t *testing.T) {
+		n := g.SafeURLAttr("src", "data:image/png;base64,iVBORw0KGgo=&")
+		assert.Equal(t, ` src="data:image/png;base64,iVBORw0KGgo=&amp;"`, n)
+	})
+}
+
+func TestCSSAttr(t *testing.T) {
+	tests := []struct {
+		css      string
+		expected string
+	}{
+		{css: "color: red; width: calc(100% - 2px)", expected: ` style="color: red; width: calc(100% - 2px)"`},
+		{css: `font-family: "Party Hat"`, expected: ` style="font-family: &#34;Party Hat&#34;"`},
+		{css: "background: url(/hat.png)", expected: ` style="background: url(/hat.png)"`},
+		{css: "width: expression(alert(1))", expected: ` style="ZgotmplZ"`},
+		{css: "width: EXPRESSION(alert(1))", expected: ` style="ZgotmplZ"`},
+		{css: "background: url(javascript:alert(1))", expected: ` style="ZgotmplZ"`},
+		{css: `background: url(java\73 cript:alert(1))`, expected: ` style="ZgotmplZ"`},
+		{css: "width: expr/**/ession(alert(1))", expected: ` style="ZgotmplZ"`},
+		{css: "-moz-binding: url(hat.xml)", expected: ` style="ZgotmplZ"`},
+		{css: "} body { color: red", expected: ` style="ZgotmplZ"`},
+		{css: "</style><script>", expected: ` style="ZgotmplZ"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.css, func(t *testing.T) {
+			assert.Equal(t, test.expected, g.CSSAttr("style", test.css))
+		})
+	}
+}
+
+func TestTrustedScript(t *testing.T) {
+	t.Run("renders the script unescaped", func(t *testing.T) {
+		assert.Equal(t, `<script>if (a < b) {}</script>`, g.El("script", g.TrustedScript("if (a < b) {}")))
+	})
+
+	t.Run("returns render error on cannot write", func(t *testing.T) {
+		err := g.TrustedScript("alert(1)").Render(&erroringWriter{})
+		assert.Error(t, err)
+	})
+}
+
+func TestJSString(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected string
+	}{
+		{s: "party hat", expected: `"party hat"`},
+		{s: `"quoted" \ 'single'`, expected: `"\"quoted\" \\ 'single'"`},
+		{s: "</script><script>alert(1)", expected: `"\u003c/script\u003e\u003cscript\u003ealert(1)"`},
+		{s: "a & b\n\u2028", expected: `"a \u0026 b\n\u2028"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.s, func(t *testing.T) {
+			if actual := g.JSString(test.s); string(actual) != test.expected {
+				t.Fatalf("expected %v but got %v", test.expected, actual)
+			}
+		})
+	}
+}
+
+func ExampleURLAttr() {
+	e := g.El("a", g.URLAttr("href", "javascript:alert(1)"), g.Text("Party"))
+	_ = e.Render(os.Stdout)
+	// Output: <a href="#ZgotmplZ">Party</a>
+}
+
+func ExampleJSString() {
+	name := "</script><script>alert(1)</script>"
+	e := g.El("script", "const name = "+g.JSString(name)+";")
+	_ = e.Render(os.Stdout)
+	// Output: <script>const name = "\u003c/script\u003e\u003cscript\u003ealert(1)\u003c/script\u003e";</script>
+}
@@ -0,0 +1,374 @@
+// Package forms provides HTML forms built from Go structs, with values, decoding, and validation messages.
+//
+// Each exported field of the struct is a form field, configured with struct tags:
+//   - form: the field name, used for the name and id attributes. Defaults to the Go field name. Use "-" to skip the field.
+//   - label: the label text. Defaults to the field name.
+//   - type: the input type, like "email" or "password", or "select" and "textarea" for those elements.
+//     Defaults to "checkbox" for bool fields, "number" for numeric fields, and "text" for string fields.
+//   - required: "true" if the field is required. A required checkbox must be checked.
+//   - min and max: the minimum and maximum value for numeric fields, and the minimum and maximum length for string fields.
+//   - pattern: a regular expression the whole value of a string field must match, if it's not empty.
+//   - options: comma-separated option values for select fields.
+//
+// Supported field types are string, bool, and the integer and floating-point types.
+//
+// For example:
+//
+//	type Signup struct {
+//		Email      string `form:"email" label:"Email" type:"email" required:"true"`
+//		Name       string `form:"name" label:"Name" max:"50"`
+//		Age        int    `form:"age" label:"Age" min:"18"`
+//		Size       string `form:"size" label:"Hat size" type:"select" options:"S,M,L"`
+//		Newsletter bool   `form:"newsletter" label:"Send me news"`
+//	}
+package forms
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	g "maragu.dev/gomponents"
+	h "maragu.dev/gomponents/html"
+)
+
+// Errors maps field names to validation messages, rendered next to the fields by [Form] and [Fields].
+// The field names are the ones from the form tag, see the package documentation.
+type Errors map[string]string
+
+// Form returns a form element with the fields of the struct v, see [Fields].
+// The children are added after the fields, like attributes and a submit button.
+// The method is "post" unless the children have a method attribute, since [Decode] only reads the request body.
+// It panics if v is not a struct or a pointer to a struct, or if the struct tags are invalid.
+func Form(v interface{}, errs Errors, children ...g.Node) g.Node {
+	if _, ok := g.El("form", children...).(g.ElementNode).Attribute("method"); !ok {
+		children = append([]g.Node{h.Method("post")}, children...)
+	}
+	return h.Form(Fields(v, errs), g.Group(children))
+}
+
+// Fields returns the form fields of the struct v, each in a div element with a label, filled with the value from v.
+// If errs has a message for a field, the field is marked with aria-invalid and described by the message,
+// which is rendered after it in a p element with the id "<name>-error".
+// It panics if v is not a struct or a pointer to a struct, or if the struct tags are invalid.
+func Fields(v interface{}, errs Errors) g.Node {
+	fields, err := parseFields(reflect.Indirect(reflect.ValueOf(v)))
+	if err != nil {
+		panic(err.Error())
+	}
+
+	var nodes g.Group
+	for _, f := range fields {
+		nodes = append(nodes, f.render(errs[f.name]))
+	}
+	return nodes
+}
+
+// Decode the form values in r.PostForm into the struct pointed to by v, and validate them against the struct tags.
+// The form is parsed with [http.Request.ParseForm] first.
+// Values that can't be parsed and invalid values are returned as [Errors], which are empty if the form is valid.
+// The error is only for invalid arguments, like unsupported field types and invalid struct tags,
+// and forms that can't be parsed.
+func Decode(r *http.Request, v interface{}) (Errors, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return nil, errors.New("forms: v must be a pointer to a struct")
+	}
+
+	if err := r.ParseForm(); err != nil {
+		return nil, fmt.Errorf("forms: error parsing form: %w", err)
+	}
+
+	fields, err := parseFields(rv.Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	errs := Errors{}
+	for _, f := range fields {
+		if msg := f.decode(r.PostForm.Get(f.name)); msg != "" {
+			errs[f.name] = msg
+		}
+	}
+	return errs, nil
+}
+
+// field of a form, parsed from a struct field and its tags.
+type field struct {
+	name     string
+	label    string
+	typ      string
+	required bool
+	min      string
+	max      string
+	pattern  *regexp.Regexp
+	options  []string
+	value    reflect.Value
+
+	// patternSource is the pattern as given in the tag, for the pattern attribute.
+	patternSource string
+}
+
+// parseFields of the struct value v, returning an error for unsupported field types and invalid struct tags.
+func parseFields(v reflect.Value) ([]field, error) {
+	if v.Kind() != reflect.Struct {
+		return nil, errors.New("forms: v must be a struct or a pointer to a struct")
+	}
+
+	var fields []field
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() || sf.Tag.Get("form") == "-" {
+			continue
+		}
+
+		f := field{
+			name:     sf.Tag.Get("form"),
+			label:    sf.Tag.Get("label"),
+			typ:      sf.Tag.Get("type"),
+			required: sf.Tag.Get("required") == "true",
+			min:      sf.Tag.Get("min"),
+			max:      sf.Tag.Get("max"),
+			value:    v.Field(i),
+		}
+		if f.name == "" {
+			f.name = sf.Name
+		}
+		if f.label == "" {
+			f.label = f.name
+		}
+
+		kind := f.value.Kind()
+		switch {
+		case kind == reflect.String:
+			if f.typ == "" {
+				f.typ = "text"
+			}
+		case kind == reflect.Bool:
+			f.typ = "checkbox"
+		case isInt(kind) || isUint(kind) || isFloat(kind):
+			if f.typ == "" {
+				f.typ = "number"
+			}
+		default:
+			return nil, fmt.Errorf("forms: unsupported type %v of field %v", sf.Type, sf.Name)
+		}
+
+		for _, limit := range []string{f.min, f.max} {
+			if limit == "" {
+				continue
+			}
+			if _, err := strconv.ParseFloat(limit, 64); err != nil || (kind == reflect.String && !isWholeNumber(limit)) {
+				return nil, fmt.Errorf("forms: invalid min or max %q of field %v", limit, sf.Name)
+			}
+		}
+
+		if pattern := sf.Tag.Get("pattern"); pattern != "" {
+			if kind != reflect.String {
+				return nil, fmt.Errorf("forms: pattern on non-string field %v", sf.Name)
+			}
+			re, err := regexp.Compile("^(?:" + pattern + ")$")
+			if err != nil {
+				return nil, fmt.Errorf("forms: invalid pattern %q of field %v: %w", pattern, sf.Name, err)
+			}
+			f.pattern = re
+			f.patternSource = pattern
+		}
+
+		if options := sf.Tag.Get("options"); options != "" {
+			f.options = strings.Split(options, ",")
+		}
+
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+func (f field) render(errMsg string) g.Node {
+	errorID := f.name + "-error"
+	attrs := g.Group{
+		h.ID(f.name),
+		h.Name(f.name),
+		g.If(f.required, h.Required()),
+		g.If(errMsg != "", g.Group{h.Aria("invalid", "true"), h.Aria("describedby", errorID)}),
+	}
+
+	var control g.Node
+	switch f.typ {
+	case "select":
+		value := f.string()
+		control = h.Select(attrs,
+			g.Map(f.options, func(o string) g.Node {
+				return h.Option(h.Value(o), g.If(o == value, h.Selected()), g.Text(o))
+			}),
+		)
+	case "textarea":
+		control = h.Textarea(attrs, f.lengthAttrs(), g.Text(f.string()))
+	case "checkbox":
+		control = h.Input(h.Type("checkbox"), attrs, h.Value("true"), g.If(f.value.Bool(), h.Checked()))
+	default:
+		control = h.Input(h.Type(f.typ), attrs, h.Value(f.string()), f.limitAttrs(), f.lengthAttrs(),
+			g.If(f.pattern != nil, h.Pattern(f.patternSource)),
+			g.If(isFloat(f.value.Kind()), h.Step("any")),
+		)
+	}
+
+	return h.Div(
+		h.Label(h.For(f.name), g.Text(f.label)),
+		control,
+		g.If(errMsg != "", h.P(h.ID(errorID), g.Text(errMsg))),
+	)
+}
+
+// limitAttrs for numeric fields.
+func (f field) limitAttrs() g.Node {
+	if f.value.Kind() == reflect.String {
+		return nil
+	}
+	return g.Group{g.If(f.min != "", h.Min(f.min)), g.If(f.max != "", h.Max(f.max))}
+}
+
+// lengthAttrs for string fields.
+func (f field) lengthAttrs() g.Node {
+	if f.value.Kind() != reflect.String {
+		return nil
+	}
+	return g.Group{g.If(f.min != "", h.MinLength(f.min)), g.If(f.max != "", h.MaxLength(f.max))}
+}
+
+// string value of the field.
+func (f field) string() string {
+	kind := f.value.Kind()
+	switch {
+	case kind == reflect.String:
+		return f.value.String()
+	case isInt(kind):
+		return strconv.FormatInt(f.value.Int(), 10)
+	case isUint(kind):
+		return strconv.FormatUint(f.value.Uint(), 10)
+	case isFloat(kind):
+		return strconv.FormatFloat(f.value.Float(), 'f', -1, f.value.Type().Bits())
+	default:
+		return ""
+	}
+}
+
+// decode the form value s into the field, and return a validation message if it's invalid.
+func (f field) decode(s string) string {
+	kind := f.value.Kind()
+
+	if kind == reflect.Bool {
+		checked := s != "" && s != "false"
+		f.value.SetBool(checked)
+		if f.required && !checked {
+			return "Must be checked."
+		}
+		return ""
+	}
+
+	if s == "" {
+		f.value.Set(reflect.Zero(f.value.Type()))
+		if f.required {
+			return "Required."
+		}
+		return ""
+	}
+
+	switch {
+	case kind == reflect.String:
+		f.value.SetString(s)
+		length := utf8.RuneCountInString(s)
+		if f.min != "" && length < mustAtoi(f.min) {
+			return fmt.Sprintf("Must be at least %v characters.", f.min)
+		}
+		if f.max != "" && length > mustAtoi(f.max) {
+			return fmt.Sprintf("Must be at most %v characters.", f.max)
+		}
+		if f.pattern != nil && !f.pattern.MatchString(s) {
+			return "Must match the requested format."
+		}
+		if f.typ == "select" && !contains(f.options, s) {
+			return "Must be one of the options."
+		}
+		return ""
+
+	case isInt(kind):
+		i, err := strconv.ParseInt(s, 10, f.value.Type().Bits())
+		if err != nil {
+			return "Must be a whole number."
+		}
+		f.value.SetInt(i)
+		return f.checkLimits(float64(i))
+
+	case isUint(kind):
+		u, err := strconv.ParseUint(s, 10, f.value.Type().Bits())
+		if err != nil {
+			return "Must be a whole number of zero or more."
+		}
+		f.value.SetUint(u)
+		return f.checkLimits(float64(u))
+
+	default:
+		v, err := strconv.ParseFloat(s, f.value.Type().Bits())
+		if err != nil {
+			return "Must be a number."
+		}
+		f.value.SetFloat(v)
+		return f.checkLimits(v)
+	}
+}
+
+// checkLimits of the numeric value v, returning a validation message if it's outside of min or max.
+func (f field) checkLimits(v float64) string {
+	if f.min != "" && v < mustParseFloat(f.min) {
+		return fmt.Sprintf("Must be at least %v.", f.min)
+	}
+	if f.max != "" && v > mustParseFloat(f.max) {
+		return fmt.Sprintf("Must be at most %v.", f.max)
+	}
+	return ""
+}
+
+func isInt(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+func isUint(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uintptr
+}
+
+func isFloat(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+func isWholeNumber(s string) bool {
+	_, err := strconv.Atoi(s)
+	return err == nil
+}
+
+// mustAtoi parses s, which has been checked in parseFields.
+func mustAtoi(s string) int {
+	i, _ := strconv.Atoi(s)
+	return i
+}
+
+// mustParseFloat parses s, which has been checked in parseFields.
+func mustParseFloat(s string) float64 {
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
@@ -0,0 +1,227 @@
+package forms_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	g "maragu.dev/gomponents"
+	"maragu.dev/gomponents/forms"
+	. "maragu.dev/gomponents/html"
+	"maragu.dev/gomponents/internal/assert"
+)
+
+type hat struct {
+	Name     string  `form:"name" label:"Name" required:"true" min:"2" max:"10"`
+	Email    string  `form:"email" label:"Email" type:"email"`
+	Code     string  `form:"code" label:"Code" pattern:"[a-z]+"`
+	Size     string  `form:"size" label:"Size" type:"select" options:"S,M,L"`
+	Notes    string  `form:"notes" label:"Notes" type:"textarea" max:"100"`
+	Count    int     `form:"count" label:"Count" min:"1" max:"10"`
+	Width    uint8   `form:"width"`
+	Price    float64 `form:"price" label:"Price" min:"0.5"`
+	Party    bool    `form:"party" label:"Party" required:"true"`
+	Internal string  `form:"-"`
+	Default  string
+	private  string
+}
+
+func TestForm(t *testing.T) {
+	t.Run("renders a form with the fields and children", func(t *testing.T) {
+		type signup struct {
+			Email string `form:"email" label:"Email" type:"email" required:"true"`
+		}
+		n := forms.Form(signup{Email: "me@example.com"}, nil, Method("post"), Button(g.Text("Sign up")))
+		assert.Equal(t, `<form method="post"><div><label for="email">Email</label>`+
+			`<input type="email" id="email" name="email" required value="me@example.com"></div>`+
+			`<button>Sign up</button></form>`, n)
+	})
+
+	t.Run("posts the form unless the children have a method attribute", func(t *testing.T) {
+		type search struct {
+			Query string `form:"q" label:"Search"`
+		}
+		assert.Equal(t, `<form method="post"><div><label for="q">Search</label><input type="text" id="q" name="q" value=""></div></form>`,
+			forms.Form(search{}, nil))
+		assert.Equal(t, `<form method="get"><div><label for="q">Search</label><input type="text" id="q" name="q" value=""></div></form>`,
+			forms.Form(search{}, nil, g.Group{Method("get")}))
+	})
+
+	t.Run("panics if v is not a struct", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r != "forms: v must be a struct or a pointer to a struct" {
+				t.Fatal("unexpected panic", r)
+			}
+		}()
+		forms.Form("hat", nil)
+	})
+}
+
+func TestFields(t *testing.T) {
+	t.Run("renders fields for each exported field from the struct tags, with values", func(t *testing.T) {
+		h := &hat{Name: "Party", Code: "abc", Size: "M", Notes: "<fun>", Count: 3, Width: 7, Price: 1.5, Party: true, Default: "yes"}
+		expected := []string{
+			`<div><label for="name">Name</label><input type="text" id="name" name="name" required value="Party" minlength="2" maxlength="10"></div>`,
+			`<div><label for="email">Email</label><input type="email" id="email" name="email" value=""></div>`,
+			`<div><label for="code">Code</label><input type="text" id="code" name="code" value="abc" pattern="[a-z]+"></div>`,
+			`<div><label for="size">Size</label><select id="size" name="size"><option value="S">S</option><option value="M" selected>M</option><option value="L">L</option></select></div>`,
+			`<div><label for="notes">Notes</label><textarea id="notes" name="notes" maxlength="100">&lt;fun&gt;</textarea></div>`,
+			`<div><label for="count">Count</label><input type="number" id="count" name="count" value="3" min="1" max="10"></div>`,
+			`<div><label for="width">width</label><input type="number" id="width" name="width" value="7"></div>`,
+			`<div><label for="price">Price</label><input type="number" id="price" name="price" value="1.5" min="0.5" step="any"></div>`,
+			`<div><label for="party">Party</label><input type="checkbox" id="party" name="party" required value="true" checked></div>`,
+			`<div><label for="Default">Default</label><input type="text" id="Default" name="Default" value="yes"></div>`,
+		}
+		assert.Equal(t, strings.Join(expected, ""), forms.Fields(h, nil))
+	})
+
+	t.Run("renders validation messages with aria attributes", func(t *testing.T) {
+		type signup struct {
+			Email string `form:"email" label:"Email"`
+			Name  string `form:"name" label:"Name"`
+		}
+		n := forms.Fields(signup{}, forms.Errors{"email": "Required."})
+		assert.Equal(t, `<div><label for="email">Email</label><input type="text" id="email" name="email" aria-invalid="true" aria-describedby="email-error" value="">`+
+			`<p id="email-error">Required.</p></div>`+
+			`<div><label for="name">Name</label><input type="text" id="name" name="name" value=""></div>`, n)
+	})
+
+	t.Run("panics on invalid struct tags and unsupported types", func(t *testing.T) {
+		for _, test := range []struct {
+			v        interface{}
+			expected string
+		}{
+			{v: struct{ Hats []string }{}, expected: "forms: unsupported type []string of field Hats"},
+			{v: struct {
+				Name string `min:"a"`
+			}{}, expected: `forms: invalid min or max "a" of field Name`},
+			{v: struct {
+				Name string `max:"1.5"`
+			}{}, expected: `forms: invalid min or max "1.5" of field Name`},
+			{v: struct {
+				Count int `pattern:"[0-9]"`
+			}{}, expected: "forms: pattern on non-string field Count"},
+			{v: struct {
+				Name string `pattern:"("`
+			}{}, expected: "forms: invalid pattern \"(\" of field Name: error parsing regexp: missing closing ): `^(?:()$`"},
+		} {
+			t.Run(test.expected, func(t *testing.T) {
+				defer func() {
+					if r := recover(); r != test.expected {
+						t.Fatal("unexpected panic", r)
+					}
+				}()
+				forms.Fields(test.v, nil)
+			})
+		}
+	})
+}
+
+func TestDecode(t *testing.T) {
+	t.Run("decodes form values into the struct", func(t *testing.T) {
+		var h hat
+		errs, err := forms.Decode(post(url.Values{
+			"name": {"Party"}, "email": {"me@example.com"}, "code": {"abc"}, "size": {"L"}, "notes": {"Fun"},
+			"count": {"5"}, "width": {"200"}, "price": {"2.25"}, "party": {"true"}, "Default": {"yes"}, "Internal": {"no"},
+		}), &h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(errs) != 0 {
+			t.Fatal("unexpected errors", errs)
+		}
+		expected := hat{Name: "Party", Email: "me@example.com", Code: "abc", Size: "L", Notes: "Fun", Count: 5, Width: 200,
+			Price: 2.25, Party: true, Default: "yes"}
+		if !reflect.DeepEqual(h, expected) {
+			t.Fatalf("expected %+v but got %+v", expected, h)
+		}
+	})
+
+	t.Run("returns validation errors for invalid values", func(t *testing.T) {
+		h := hat{Name: "Party", Count: 3, Party: true}
+		errs, err := forms.Decode(post(url.Values{
+			"code": {"ABC"}, "size": {"XL"}, "notes": {strings.Repeat("a", 101)}, "count": {"11"}, "width": {"256"}, "price": {"0.25"},
+		}), &h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := forms.Errors{
+			"name":  "Required.",
+			"code":  "Must match the requested format.",
+			"size":  "Must be one of the options.",
+			"notes": "Must be at most 100 characters.",
+			"count": "Must be at most 10.",
+			"width": "Must be a whole number of zero or more.",
+			"price": "Must be at least 0.5.",
+			"party": "Must be checked.",
+		}
+		if !reflect.DeepEqual(errs, expected) {
+			t.Fatalf("expected %v but got %v", expected, errs)
+		}
+		if h.Name != "" || h.Count != 11 || h.Party {
+			t.Fatal("unexpected values", h)
+		}
+	})
+
+	t.Run("returns validation errors for values that can't be parsed", func(t *testing.T) {
+		var h hat
+		errs, err := forms.Decode(post(url.Values{"name": {"P"}, "count": {"1.5"}, "price": {"cheap"}}), &h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if errs["name"] != "Must be at least 2 characters." || errs["count"] != "Must be a whole number." || errs["price"] != "Must be a number." {
+			t.Fatal("unexpected errors", errs)
+		}
+	})
+
+	t.Run("returns an error if v is not a pointer to a struct", func(t *testing.T) {
+		_, err := forms.Decode(post(nil), hat{})
+		assert.Error(t, err)
+	})
+
+	t.Run("returns an error on invalid struct tags and unsupported types", func(t *testing.T) {
+		var v struct{ Hats []string }
+		_, err := forms.Decode(post(nil), &v)
+		if err == nil || err.Error() != "forms: unsupported type []string of field Hats" {
+			t.Fatal("unexpected error", err)
+		}
+
+		var w struct {
+			Name string `min:"a"`
+		}
+		_, err = forms.Decode(post(nil), &w)
+		if err == nil || err.Error() != `forms: invalid min or max "a" of field Name` {
+			t.Fatal("unexpected error", err)
+		}
+	})
+
+	t.Run("returns an error if the form can't be parsed", func(t *testing.T) {
+		r := post(nil)
+		r.Body = http.NoBody
+		r.URL.RawQuery = "%"
+		_, err := forms.Decode(r, &hat{})
+		assert.Error(t, err)
+	})
+}
+
+func post(values url.Values) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(values.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return r
+}
+
+func ExampleForm() {
+	type signup struct {
+		Email string `form:"email" label:"Email" type:"email" required:"true"`
+		Size  string `form:"size" label:"Hat size" type:"select" options:"S,M,L"`
+	}
+
+	n := forms.Form(signup{Size: "M"}, forms.Errors{"email": "Required."}, Button(g.Text("Sign up")))
+	_ = n.Render(os.Stdout)
+	// Output:
+	// <form method="post"><div><label for="email">Email</label><input type="email" id="email" name="email" required aria-invalid="true" aria-describedby="email-error" value=""><p id="email-error">Required.</p></div><div><label for="size">Hat size</label><select id="size" name="size"><option value="S">S</option><option value="M" selected>M</option><option value="L">L</option></select></div><button>Sign up</button></form>
+}
@@ -12,8 +12,15 @@
 //
 // See also helper functions [Map], [If], and [Iff] for mapping data to nodes and inserting them conditionally.
 //
+// Nodes that need a [context.Context] when rendering can implement [ContextRenderer], or use [ContextFunc].
+// Render them with [RenderCtx].
+//
 // There's also the [Group] type, which is a slice of [Node]-s that can be rendered as one [Node].
 //
+// Nodes created with [El], [Attr], [Text], [Raw], and [Group] are values of the inspectable types
+// [ElementNode], [AttributeNode], [TextNode], [RawNode], and [Group], respectively.
+// Use [Walk] and [Inspect] to visit a tree of them, and [Transform] to rewrite it.
+//
 // For basic HTML elements and attributes, see the package html.
 //
 // For higher-level HTML components, see the package components.
@@ -22,6 +29,7 @@
 package gomponents
 
 import (
+	"context"
 	"fmt"
 	"html/template"
 	"io"
@@ -87,53 +95,136 @@ var (
 // See https://dev.w3.org/html5/spec-LC/syntax.html#elements-0 for how elements are rendered.
 // No tags are ever omitted from normal tags, even though it's allowed for elements given at
 // https://dev.w3.org/html5/spec-LC/syntax.html#optional-tags
+// (see [RenderMinified] for that).
 // If an element is a void element, non-attribute children nodes are ignored.
 // The name is rendered unescaped and must be a trusted value, never user-controlled data.
 // Use this if no convenience creator exists in the html package.
+// The returned [Node] is an [ElementNode], which can be inspected.
 func El(name string, children ...Node) Node {
-	return NodeFunc(func(w io.Writer) error {
-		if _, err := w.Write(lt); err != nil {
-			return err
-		}
+	return ElementNode{Name: name, Children: children}
+}
 
-		if _, err := io.WriteString(w, name); err != nil {
+// Compile-time check that [ElementNode] implements [fmt.Stringer], [Node], [ContextRenderer] and [nodeTypeDescriber].
+var _ interface {
+	fmt.Stringer
+	Node
+	ContextRenderer
+	nodeTypeDescriber
+} = ElementNode{}
+
+// ElementNode is an element DOM [Node] with a name and child Nodes, as created by [El].
+// Its fields can be inspected to find out what the element consists of, see also [Walk].
+type ElementNode struct {
+	Name     string
+	Children []Node
+}
+
+// Render satisfies [Node].
+func (e ElementNode) Render(w io.Writer) error {
+	if _, err := w.Write(lt); err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, e.Name); err != nil {
+		return err
+	}
+
+	for _, c := range e.Children {
+		if err := renderChild(w, c, AttributeType); err != nil {
 			return err
 		}
+	}
 
-		for _, c := range children {
-			if err := renderChild(w, c, AttributeType); err != nil {
-				return err
-			}
-		}
+	if _, err := w.Write(gt); err != nil {
+		return err
+	}
 
-		if _, err := w.Write(gt); err != nil {
+	if isVoidElement(e.Name) {
+		return nil
+	}
+
+	for _, c := range e.Children {
+		if err := renderChild(w, c, ElementType); err != nil {
 			return err
 		}
+	}
 
-		if isVoidElement(name) {
-			return nil
-		}
+	return e.renderEndTag(w)
+}
 
-		for _, c := range children {
-			if err := renderChild(w, c, ElementType); err != nil {
-				return err
-			}
-		}
+// RenderContext satisfies [ContextRenderer].
+// It's like Render, which is kept free of context checks so rendering without a context stays fast.
+// The context is passed on to children that implement [ContextRenderer].
+// If the context is done, rendering stops and the context error is returned.
+// If the context has a nonce, see [WithCSPNonce], it's added to script, style, and stylesheet link elements.
+func (e ElementNode) RenderContext(ctx context.Context, w io.Writer) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if _, err := w.Write(lt); err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, e.Name); err != nil {
+		return err
+	}
 
-		if _, err := w.Write(ltSlash); err != nil {
+	if nonce := cspNonce(ctx, e); nonce != "" {
+		if err := (AttributeNode{Name: "nonce", Value: nonce}).Render(w); err != nil {
 			return err
 		}
+	}
 
-		if _, err := io.WriteString(w, name); err != nil {
+	for _, c := range e.Children {
+		if err := renderChildContext(ctx, w, c, AttributeType); err != nil {
 			return err
 		}
+	}
+
+	if _, err := w.Write(gt); err != nil {
+		return err
+	}
 
-		if _, err := w.Write(gt); err != nil {
+	if isVoidElement(e.Name) {
+		return nil
+	}
+
+	for _, c := range e.Children {
+		if err := renderChildContext(ctx, w, c, ElementType); err != nil {
 			return err
 		}
+	}
 
-		return nil
-	})
+	return e.renderEndTag(w)
+}
+
+func (e ElementNode) renderEndTag(w io.Writer) error {
+	if _, err := w.Write(ltSlash); err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, e.Name); err != nil {
+		return err
+	}
+
+	if _, err := w.Write(gt); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Type satisfies [nodeTypeDescriber].
+func (ElementNode) Type() NodeType {
+	return ElementType
+}
+
+// String satisfies [fmt.Stringer].
+func (e ElementNode) String() string {
+	var b strings.Builder
+	_ = e.Render(&b)
+	return b.String()
 }
 
 // renderChild c to the given writer w if the node type is desiredType.
@@ -171,6 +262,47 @@ func renderChild(w io.Writer, c Node, desiredType NodeType) error {
 	return nil
 }
 
+// renderChildContext is like renderChild, but renders c with ctx if it implements [ContextRenderer].
+func renderChildContext(ctx context.Context, w io.Writer, c Node, desiredType NodeType) error {
+	if c == nil {
+		return nil
+	}
+
+	if g, ok := c.(Group); ok {
+		for _, groupC := range g {
+			if err := renderChildContext(ctx, w, groupC, desiredType); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	switch desiredType {
+	case ElementType:
+		if p, ok := c.(nodeTypeDescriber); !ok || p.Type() == desiredType {
+			if err := renderWithContext(ctx, w, c); err != nil {
+				return err
+			}
+		}
+	case AttributeType:
+		if p, ok := c.(nodeTypeDescriber); ok && p.Type() == desiredType {
+			if err := renderWithContext(ctx, w, c); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// renderWithContext renders n with ctx if n implements [ContextRenderer], and normally otherwise.
+func renderWithContext(ctx context.Context, w io.Writer, n Node) error {
+	if cr, ok := n.(ContextRenderer); ok {
+		return cr.RenderContext(ctx, w)
+	}
+	return n.Render(w)
+}
+
 // isVoidElement reports whether the named element is a void element that doesn't have an end tag.
 // See https://dev.w3.org/html5/spec-LC/syntax.html#void-elements
 func isVoidElement(name string) bool {
@@ -193,128 +325,150 @@ var (
 // More than one value makes [Attr] panic.
 // The name is rendered unescaped and must be a trusted value, never user-controlled data; the value is escaped.
 // Use this if no convenience creator exists in the html package.
+// The returned [Node] is an [AttributeNode], which can be inspected.
 func Attr(name string, value ...string) Node {
 	switch len(value) {
 	case 0:
-		return booleanAttr(name)
+		return AttributeNode{Name: name, Boolean: true}
 	case 1:
-		return valueAttr(name, value[0])
+		return AttributeNode{Name: name, Value: value[0]}
 	default:
 		panic("attribute must be just name or name and value pair")
 	}
 }
 
-// booleanAttr creates a boolean attribute Node with just a name.
-func booleanAttr(name string) Node {
-	return attrFunc(func(w io.Writer) error {
-		if _, err := w.Write(space); err != nil {
-			return err
-		}
-
-		if _, err := io.WriteString(w, name); err != nil {
-			return err
-		}
-
-		return nil
-	})
+// Compile-time check that [AttributeNode] implements [fmt.Stringer], [Node] and [nodeTypeDescriber].
+var _ interface {
+	fmt.Stringer
+	Node
+	nodeTypeDescriber
+} = AttributeNode{}
+
+// AttributeNode is an attribute DOM [Node] with a name and value, as created by [Attr].
+// If Boolean is true, it's a name-only attribute (like "required"), and Value is ignored.
+// The Value is unescaped, and is escaped when rendered.
+type AttributeNode struct {
+	Name    string
+	Value   string
+	Boolean bool
 }
 
-// valueAttr creates a name-value attribute Node.
-func valueAttr(name, value string) Node {
-	return attrFunc(func(w io.Writer) error {
-		if _, err := w.Write(space); err != nil {
-			return err
-		}
-
-		if _, err := io.WriteString(w, name); err != nil {
-			return err
-		}
-
-		if _, err := w.Write(equalQuote); err != nil {
-			return err
-		}
-
-		if _, err := io.WriteString(w, template.HTMLEscapeString(value)); err != nil {
-			return err
-		}
+// Render satisfies [Node].
+func (a AttributeNode) Render(w io.Writer) error {
+	if _, err := w.Write(space); err != nil {
+		return err
+	}
 
-		if _, err := w.Write(quote); err != nil {
-			return err
-		}
+	if _, err := io.WriteString(w, a.Name); err != nil {
+		return err
+	}
 
+	if a.Boolean {
 		return nil
-	})
-}
+	}
 
-// Compile-time check that [attrFunc] implements [fmt.Stringer], [Node] and [nodeTypeDescriber].
-var _ interface {
-	fmt.Stringer
-	Node
-	nodeTypeDescriber
-} = (attrFunc)(nil)
+	if _, err := w.Write(equalQuote); err != nil {
+		return err
+	}
 
-// attrFunc is a render function that is also a [Node] of [AttributeType].
-// It's basically the same as [NodeFunc], but for attributes.
-type attrFunc func(io.Writer) error
+	if _, err := io.WriteString(w, template.HTMLEscapeString(a.Value)); err != nil {
+		return err
+	}
 
-// Render satisfies [Node].
-func (a attrFunc) Render(w io.Writer) error {
-	return a(w)
+	if _, err := w.Write(quote); err != nil {
+		return err
+	}
+
+	return nil
 }
 
 // Type satisfies [nodeTypeDescriber].
-func (attrFunc) Type() NodeType {
+func (AttributeNode) Type() NodeType {
 	return AttributeType
 }
 
 // String satisfies [fmt.Stringer].
-func (a attrFunc) String() string {
+func (a AttributeNode) String() string {
 	var b strings.Builder
 	_ = a.Render(&b)
 	return b.String()
 }
 
 // Text creates a text DOM [Node] that Renders the escaped string t.
+// The returned [Node] is a [TextNode], which can be inspected.
 func Text(t string) Node {
-	return raw(template.HTMLEscapeString(t))
+	return TextNode(t)
 }
 
 // Textf creates a text DOM [Node] that Renders the interpolated and escaped string format.
+// The returned [Node] is a [TextNode], which can be inspected.
 func Textf(format string, a ...interface{}) Node {
-	return raw(template.HTMLEscapeString(fmt.Sprintf(format, a...)))
+	return TextNode(fmt.Sprintf(format, a...))
+}
+
+// Compile-time check that [TextNode] implements [fmt.Stringer], [Node], and [nodeTypeDescriber].
+var _ interface {
+	fmt.Stringer
+	Node
+	nodeTypeDescriber
+} = TextNode("")
+
+// TextNode is a text DOM [Node] that Renders the escaped, underlying string, as created by [Text] and [Textf].
+// The underlying string is the unescaped text.
+type TextNode string
+
+// Render satisfies [Node].
+func (t TextNode) Render(w io.Writer) error {
+	_, err := io.WriteString(w, template.HTMLEscapeString(string(t)))
+	return err
+}
+
+// String satisfies [fmt.Stringer].
+func (t TextNode) String() string {
+	return template.HTMLEscapeString(string(t))
+}
+
+// Type satisfies [nodeTypeDescriber].
+func (TextNode) Type() NodeType {
+	return ElementType
 }
 
-// Compile-time check that [raw] implements [fmt.Stringer], [Node], and [nodeTypeDescriber].
+// Compile-time check that [RawNode] implements [fmt.Stringer], [Node], and [nodeTypeDescriber].
 var _ interface {
 	fmt.Stringer
 	Node
 	nodeTypeDescriber
-} = raw("")
+} = RawNode("")
 
-// raw is a text DOM [Node] that just Renders the unescaped, underlying string.
-type raw string
+// RawNode is a text DOM [Node] that just Renders the unescaped, underlying string, as created by [Raw] and [Rawf].
+type RawNode string
 
-func (r raw) Render(w io.Writer) error {
+// Render satisfies [Node].
+func (r RawNode) Render(w io.Writer) error {
 	_, err := io.WriteString(w, string(r))
 	return err
 }
 
-func (r raw) String() string {
+// String satisfies [fmt.Stringer].
+func (r RawNode) String() string {
 	return string(r)
 }
 
-func (r raw) Type() NodeType {
+// Type satisfies [nodeTypeDescriber].
+func (RawNode) Type() NodeType {
 	return ElementType
 }
 
 // Raw creates a text DOM [Node] that just Renders the unescaped string t.
+// The returned [Node] is a [RawNode], which can be inspected.
 func Raw(t string) Node {
-	return raw(t)
+	return RawNode(t)
 }
 
 // Rawf creates a text DOM [Node] that just Renders the interpolated and unescaped string format.
+// The returned [Node] is a [RawNode], which can be inspected.
 func Rawf(format string, a ...interface{}) Node {
-	return raw(fmt.Sprintf(format, a...))
+	return RawNode(fmt.Sprintf(format, a...))
 }
 
 // Map a slice of anything to a [Group] (which is just a slice of [Node]-s).
@@ -326,10 +480,11 @@ func Map[T any](ts []T, cb func(T) Node) Group {
 	return nodes
 }
 
-// Compile-time check that [Group] implements [fmt.Stringer] and [Node].
+// Compile-time check that [Group] implements [fmt.Stringer], [Node] and [ContextRenderer].
 var _ interface {
 	fmt.Stringer
 	Node
+	ContextRenderer
 } = (Group)(nil)
 
 // Group a slice of [Node]-s into one Node, while still being usable like a regular slice of [Node]-s.
@@ -354,6 +509,21 @@ func (g Group) Render(w io.Writer) error {
 	return nil
 }
 
+// RenderContext satisfies [ContextRenderer].
+// The context is passed on to children that implement [ContextRenderer].
+// If the context is done, rendering stops and the context error is returned.
+func (g Group) RenderContext(ctx context.Context, w io.Writer) error {
+	for _, c := range g {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := renderChildContext(ctx, w, c, ElementType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // If condition is true, return the given [Node]. Otherwise, return nil.
 // This helper function is good for inlining elements conditionally.
 // If it's important that the given [Node] is only evaluated if condition is true
@@ -4,7 +4,7 @@ import "testing"
 
 func TestRaw(t *testing.T) {
 	t.Run("r.String() == string(r)", func(t *testing.T) {
-		r := raw("<p>raw</p>")
+		r := RawNode("<p>raw</p>")
 		if r.String() != string(r) {
 			t.Fail()
 		}
@@ -63,6 +63,23 @@ func TestAttr(t *testing.T) {
 		a := g.Attr(`id`, `hat"><script`)
 		assert.Equal(t, ` id="hat&#34;&gt;&lt;script"`, a)
 	})
+
+	t.Run("returns an inspectable attribute node", func(t *testing.T) {
+		a, ok := g.Attr("id", "hat<").(g.AttributeNode)
+		if !ok || a.Name != "id" || a.Value != "hat<" || a.Boolean {
+			t.Fatal("unexpected attribute node", a)
+		}
+
+		a, ok = g.Attr("required").(g.AttributeNode)
+		if !ok || a.Name != "required" || a.Value != "" || !a.Boolean {
+			t.Fatal("unexpected attribute node", a)
+		}
+	})
+
+	t.Run("renders an empty value if not boolean", func(t *testing.T) {
+		a := g.AttributeNode{Name: "value"}
+		assert.Equal(t, ` value=""`, a)
+	})
 }
 
 func ExampleAttr_bool() {
@@ -130,6 +147,16 @@ func TestEl(t *testing.T) {
 		assert.Equal(t, `<div><br><br></div>`, e)
 	})
 
+	t.Run("returns an inspectable element node", func(t *testing.T) {
+		e, ok := g.El("div", g.Attr("class", "hat"), g.Text("party")).(g.ElementNode)
+		if !ok || e.Name != "div" || len(e.Children) != 2 {
+			t.Fatal("unexpected element node", e)
+		}
+		if e.String() != `<div class="hat">party</div>` {
+			t.Fatal("unexpected string", e.String())
+		}
+	})
+
 	t.Run("returns render error on cannot write", func(t *testing.T) {
 		// This weird little constructs makes sure we test error handling of all writes
 		for i := 0; i <= 33; i++ {
@@ -186,6 +213,13 @@ func TestText(t *testing.T) {
 		e := g.Text("<div>")
 		assert.Equal(t, "&lt;div&gt;", e)
 	})
+
+	t.Run("returns an inspectable text node with the unescaped text", func(t *testing.T) {
+		e, ok := g.Text("<div>").(g.TextNode)
+		if !ok || string(e) != "<div>" {
+			t.Fatal("unexpected text node", e)
+		}
+	})
 }
 
 func ExampleText() {
@@ -212,6 +246,13 @@ func TestRaw(t *testing.T) {
 		e := g.Raw("<div>")
 		assert.Equal(t, "<div>", e)
 	})
+
+	t.Run("returns an inspectable raw node", func(t *testing.T) {
+		e, ok := g.Raw("<div>").(g.RawNode)
+		if !ok || string(e) != "<div>" {
+			t.Fatal("unexpected raw node", e)
+		}
+	})
 }
 
 func ExampleRaw() {
@@ -84,8 +84,9 @@ func Accept(v string) g.Node {
 	return g.Attr("accept", v)
 }
 
+// Action is a URL attribute, so URLs with unsafe schemes are sanitized. See [g.URLAttr].
 func Action(v string) g.Node {
-	return g.Attr("action", v)
+	return g.URLAttr("action", v)
 }
 
 func Alt(v string) g.Node {
@@ -110,8 +111,9 @@ func Charset(v string) g.Node {
 	return g.Attr("charset", v)
 }
 
+// CiteAttr is a URL attribute, so URLs with unsafe schemes are sanitized. See [g.URLAttr].
 func CiteAttr(v string) g.Node {
-	return g.Attr("cite", v)
+	return g.URLAttr("cite", v)
 }
 
 func Class(v string) g.Node {
@@ -153,8 +155,9 @@ func For(v string) g.Node {
 	return g.Attr("for", v)
 }
 
+// FormAction is a URL attribute, so URLs with unsafe schemes are sanitized. See [g.URLAttr].
 func FormAction(v string) g.Node {
-	return g.Attr("formaction", v)
+	return g.URLAttr("formaction", v)
 }
 
 func FormAttr(v string) g.Node {
@@ -185,8 +188,9 @@ func Hidden(v string) g.Node {
 	return g.Attr("hidden", v)
 }
 
+// Href is a URL attribute, so URLs with unsafe schemes are sanitized. See [g.URLAttr].
 func Href(v string) g.Node {
-	return g.Attr("href", v)
+	return g.URLAttr("href", v)
 }
 
 func ID(v string) g.Node {
@@ -237,6 +241,13 @@ func Name(v string) g.Node {
 	return g.Attr("name", v)
 }
 
+// On event handler attributes automatically have their name prefixed with "on", like "onclick" for the "click" event.
+// The event is rendered unescaped and must be a trusted value. The script is escaped, and must be a [g.TrustedScript]
+// to make it explicit that it's trusted code. Use [g.JSString] to embed user-controlled data in the script.
+func On(event string, script g.TrustedScript) g.Node {
+	return g.Attr("on"+event, string(script))
+}
+
 func Pattern(v string) g.Node {
 	return g.Attr("pattern", v)
 }
@@ -257,8 +268,9 @@ func PopoverTargetAction(v string) g.Node {
 	return g.Attr("popovertargetaction", v)
 }
 
+// Poster is a URL attribute, so URLs with unsafe schemes are sanitized. See [g.URLAttr].
 func Poster(v string) g.Node {
-	return g.Attr("poster", v)
+	return g.URLAttr("poster", v)
 }
 
 func Preload(v string) g.Node {
@@ -297,8 +309,9 @@ func SpellCheck(v string) g.Node {
 	return g.Attr("spellcheck", v)
 }
 
+// Src is a URL attribute, so URLs with unsafe schemes are sanitized. See [g.URLAttr].
 func Src(v string) g.Node {
-	return g.Attr("src", v)
+	return g.URLAttr("src", v)
 }
 
 func SrcSet(v string) g.Node {
@@ -309,8 +322,9 @@ func Step(v string) g.Node {
 	return g.Attr("step", v)
 }
 
+// Style is a CSS attribute, so unsafe CSS is sanitized. See [g.CSSAttr].
 func Style(v string) g.Node {
-	return g.Attr("style", v)
+	return g.CSSAttr("style", v)
 }
 
 // Deprecated: Use [Style] instead.
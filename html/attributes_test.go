@@ -151,6 +151,46 @@ func TestVariadicAttributes(t *testing.T) {
 	}
 }
 
+func TestURLAttributes(t *testing.T) {
+	tests := []struct {
+		Name string
+		Func func(string) g.Node
+	}{
+		{Name: "action", Func: Action},
+		{Name: "cite", Func: CiteAttr},
+		{Name: "formaction", Func: FormAction},
+		{Name: "href", Func: Href},
+		{Name: "poster", Func: Poster},
+		{Name: "src", Func: Src},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name+" keeps safe URLs", func(t *testing.T) {
+			n := g.El("div", test.Func("https://www.gomponents.com/?a=b&c=d"))
+			assert.Equal(t, fmt.Sprintf(`<div %v="https://www.gomponents.com/?a=b&amp;c=d"></div>`, test.Name), n)
+		})
+
+		t.Run(test.Name+" sanitizes unsafe URLs", func(t *testing.T) {
+			n := g.El("div", test.Func("javascript:alert(1)"))
+			assert.Equal(t, fmt.Sprintf(`<div %v="#ZgotmplZ"></div>`, test.Name), n)
+		})
+	}
+}
+
+func TestStyle(t *testing.T) {
+	t.Run("sanitizes unsafe CSS", func(t *testing.T) {
+		n := g.El("div", Style("width: expression(alert(1))"))
+		assert.Equal(t, `<div style="ZgotmplZ"></div>`, n)
+	})
+}
+
+func TestOn(t *testing.T) {
+	t.Run("returns an attribute which name is prefixed with on", func(t *testing.T) {
+		n := On("click", "greet("+g.JSString(`"Party" hat`)+")")
+		assert.Equal(t, ` onclick="greet(&#34;\&#34;Party\&#34; hat&#34;)"`, n)
+	})
+}
+
 func TestAria(t *testing.T) {
 	t.Run("returns an attribute which name is prefixed with aria-", func(t *testing.T) {
 		n := Aria("selected", "true")
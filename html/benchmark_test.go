@@ -290,4 +290,22 @@ func BenchmarkRealisticPage(b *testing.B) {
 			sb.Reset()
 		}
 	})
+
+	b.Run("render compiled tree", func(b *testing.B) {
+		var sb strings.Builder
+		p := g.CompileNode(page())
+		for b.Loop() {
+			_ = p.Render(&sb)
+			sb.Reset()
+		}
+	})
+
+	b.Run("render static tree", func(b *testing.B) {
+		var sb strings.Builder
+		p := g.StaticNode(page())
+		for b.Loop() {
+			_ = p.Render(&sb)
+			sb.Reset()
+		}
+	})
 }
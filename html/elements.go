@@ -6,19 +6,13 @@
 package html
 
 import (
-	"io"
-
 	g "maragu.dev/gomponents"
 )
 
 // Doctype returns a special kind of [g.Node] that prefixes its sibling with the string "<!doctype html>".
+// The returned [g.Node] is a [g.Group], so the sibling can still be inspected with [g.Walk].
 func Doctype(sibling g.Node) g.Node {
-	return g.NodeFunc(func(w io.Writer) error {
-		if _, err := w.Write([]byte("<!doctype html>")); err != nil {
-			return err
-		}
-		return sibling.Render(w)
-	})
+	return g.Group{g.Raw("<!doctype html>"), sibling}
 }
 
 func A(children ...g.Node) g.Node {
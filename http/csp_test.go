@@ -0,0 +1,61 @@
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	g "maragu.dev/gomponents"
+	ghttp "maragu.dev/gomponents/http"
+)
+
+func TestCSP(t *testing.T) {
+	t.Run("sets the header and renders nonces with a new nonce for each request", func(t *testing.T) {
+		var nonces []string
+		h := ghttp.CSP("script-src 'nonce-{nonce}'; style-src 'nonce-{nonce}'")(ghttp.Adapt(
+			func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+				nonces = append(nonces, g.CSPNonce(r.Context()))
+				return g.Group{g.El("style"), g.El("script")}, nil
+			}))
+
+		for i := 0; i < 2; i++ {
+			recorder := httptest.NewRecorder()
+			h.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			nonce := nonces[i]
+			if len(nonce) != 24 {
+				t.Fatal("unexpected nonce", nonce)
+			}
+			if header := recorder.Header().Get("Content-Security-Policy"); header != "script-src 'nonce-"+nonce+"'; style-src 'nonce-"+nonce+"'" {
+				t.Fatal("unexpected header", header)
+			}
+			if body := recorder.Body.String(); body != `<style nonce="`+nonce+`"></style><script nonce="`+nonce+`"></script>` {
+				t.Fatal("unexpected body", body)
+			}
+		}
+
+		if nonces[0] == nonces[1] {
+			t.Fatal("nonces are equal")
+		}
+	})
+
+	t.Run("adds nonces to deferred content scripts", func(t *testing.T) {
+		var nonce string
+		h := ghttp.CSP("script-src 'nonce-{nonce}'")(ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+			nonce = g.CSPNonce(r.Context())
+			return ghttp.Deferred(nil, func(ctx context.Context) g.Node {
+				return g.Text("party")
+			}), nil
+		}))
+
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		body := recorder.Body.String()
+		if strings.Count(body, "<script") != 2 || strings.Count(body, `<script nonce="`+nonce+`">`) != 2 {
+			t.Fatal("unexpected body", body)
+		}
+	})
+}
@@ -0,0 +1,154 @@
+package http
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strings"
+
+	"maragu.dev/gomponents/components"
+)
+
+// csrfCookieName is the name of the cookie with the CSRF token.
+const csrfCookieName = "csrf_token"
+
+// CSRF returns middleware that protects against cross-site request forgery with signed double-submit cookies,
+// so there's no server-side token store.
+//
+// Each client gets a random token, signed with an HMAC of the key, in an HttpOnly cookie.
+// The token is masked with a new random one-time pad for each request, so it's different in every response,
+// and compression attacks like BREACH can't recover it from the page. The masked token is put in the request context
+// with [components.WithCSRFToken], for [components.CSRFField] and [components.CSRFMeta],
+// or [components.CSRFToken] for anything else.
+// Requests with methods other than GET, HEAD, OPTIONS, and TRACE must send a masked token back, in the form field
+// named [components.CSRFFieldName] in the request body or the header named [components.CSRFHeaderName],
+// or they're rejected with status code [http.StatusForbidden] (403).
+//
+// The signature only shows that the token was issued with the key, and tokens aren't tied to sessions.
+// So attackers who can set cookies for the site, for example from another subdomain, can set a token they got
+// from the site themselves, and send it along. Only use it if everything that can set cookies for the site is trusted.
+//
+// The key must be random, secret, and at least 32 bytes long. It panics otherwise.
+func CSRF(key []byte, opts CSRFOptions) func(http.Handler) http.Handler {
+	if len(key) < 32 {
+		panic("CSRF key must be at least 32 bytes long")
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var token string
+			if c, err := r.Cookie(csrfCookieName); err == nil && isValidCSRFToken(key, c.Value) {
+				token = c.Value
+			}
+
+			if !isSafeMethod(r.Method) {
+				submitted := r.Header.Get(components.CSRFHeaderName)
+				if submitted == "" {
+					submitted = r.PostFormValue(components.CSRFFieldName)
+				}
+				if token == "" || !hmac.Equal([]byte(unmaskCSRFToken(submitted)), []byte(token)) {
+					http.Error(w, "invalid CSRF token", http.StatusForbidden)
+					return
+				}
+			}
+
+			if token == "" {
+				var err error
+				token, err = newCSRFToken(key)
+				if err != nil {
+					http.Error(w, "error generating CSRF token: "+err.Error(), http.StatusInternalServerError)
+					return
+				}
+				http.SetCookie(w, &http.Cookie{
+					Name:     csrfCookieName,
+					Value:    token,
+					Path:     "/",
+					Secure:   opts.Secure || r.TLS != nil,
+					HttpOnly: true,
+					SameSite: http.SameSiteLaxMode,
+				})
+			}
+
+			masked, err := maskCSRFToken(token)
+			if err != nil {
+				http.Error(w, "error masking CSRF token: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(components.WithCSRFToken(r.Context(), masked)))
+		})
+	}
+}
+
+// CSRFOptions for [CSRF].
+type CSRFOptions struct {
+	// Secure sets the Secure attribute on the token cookie, so browsers only send it over HTTPS.
+	// Without it, the attribute is only set for requests that came in over TLS, which they don't
+	// behind a proxy that terminates TLS, so set it if the site is served over HTTPS.
+	Secure bool
+}
+
+// isSafeMethod reports whether the request method is safe, so it doesn't need a CSRF token.
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+// newCSRFToken returns 32 random bytes and their HMAC signature with the key, base64-encoded and joined by a dot.
+func newCSRFToken(key []byte) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	value := base64.RawURLEncoding.EncodeToString(b)
+	return value + "." + signCSRFToken(key, value), nil
+}
+
+// isValidCSRFToken reports whether the token has a valid signature from the key.
+func isValidCSRFToken(key []byte, token string) bool {
+	i := strings.IndexByte(token, '.')
+	if i < 0 {
+		return false
+	}
+	return hmac.Equal([]byte(token[i+1:]), []byte(signCSRFToken(key, token[:i])))
+}
+
+// signCSRFToken value with an HMAC-SHA256 of the key, base64-encoded.
+func signCSRFToken(key []byte, value string) string {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(value)) // Writing to a hash never fails
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// maskCSRFToken with a random one-time pad of the same length, returning the pad and the token XOR-ed with it,
+// base64-encoded. See unmaskCSRFToken for the reverse.
+func maskCSRFToken(token string) (string, error) {
+	b := make([]byte, 2*len(token))
+	pad, masked := b[:len(token)], b[len(token):]
+	if _, err := rand.Read(pad); err != nil {
+		return "", err
+	}
+	for i := range masked {
+		masked[i] = token[i] ^ pad[i]
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// unmaskCSRFToken returns the token masked with maskCSRFToken, or an empty string if it's not a masked token.
+func unmaskCSRFToken(s string) string {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b)%2 != 0 {
+		return ""
+	}
+	pad, masked := b[:len(b)/2], b[len(b)/2:]
+	token := make([]byte, len(masked))
+	for i := range masked {
+		token[i] = masked[i] ^ pad[i]
+	}
+	return string(token)
+}
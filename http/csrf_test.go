@@ -0,0 +1,185 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	g "maragu.dev/gomponents"
+	"maragu.dev/gomponents/components"
+	ghttp "maragu.dev/gomponents/http"
+)
+
+func TestCSRF(t *testing.T) {
+	key := []byte(strings.Repeat("k", 32))
+	h := ghttp.CSRF(key, ghttp.CSRFOptions{})(ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+		return g.Group{components.CSRFMeta(), components.CSRFField()}, nil
+	}))
+
+	// renderedToken from the meta element in the body, which is the same as in the form field
+	renderedToken := func(t *testing.T, body string) string {
+		t.Helper()
+		m := regexp.MustCompile(`^<meta name="csrf-token" content="([^"]+)"><input type="hidden" name="csrf_token" value="([^"]+)">$`).
+			FindStringSubmatch(body)
+		if m == nil || m[1] != m[2] {
+			t.Fatal("unexpected body", body)
+		}
+		return m[1]
+	}
+
+	// getToken from a new client, returning the cookie and the rendered token
+	getToken := func(t *testing.T) (*http.Cookie, string) {
+		t.Helper()
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+		cookies := recorder.Result().Cookies()
+		if len(cookies) != 1 {
+			t.Fatal("unexpected cookies", cookies)
+		}
+		return cookies[0], renderedToken(t, recorder.Body.String())
+	}
+
+	t.Run("sets a signed token cookie and renders the masked token", func(t *testing.T) {
+		cookie, token := getToken(t)
+		if cookie.Name != "csrf_token" || !cookie.HttpOnly || cookie.SameSite != http.SameSiteLaxMode || cookie.Path != "/" {
+			t.Fatal("unexpected cookie", cookie)
+		}
+		if len(cookie.Value) != 87 || strings.Count(cookie.Value, ".") != 1 {
+			t.Fatal("unexpected token", cookie.Value)
+		}
+		if len(token) != 232 || strings.Contains(token, cookie.Value) {
+			t.Fatal("unexpected rendered token", token)
+		}
+
+		otherCookie, _ := getToken(t)
+		if cookie.Value == otherCookie.Value {
+			t.Fatal("tokens are equal")
+		}
+	})
+
+	t.Run("reuses a valid token cookie, masked differently for each request", func(t *testing.T) {
+		cookie, token := getToken(t)
+
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		request.AddCookie(cookie)
+		h.ServeHTTP(recorder, request)
+		if len(recorder.Result().Cookies()) != 0 {
+			t.Fatal("unexpected cookies", recorder.Result().Cookies())
+		}
+		if otherToken := renderedToken(t, recorder.Body.String()); otherToken == token {
+			t.Fatal("rendered tokens are equal")
+		}
+	})
+
+	t.Run("replaces a token cookie with an invalid signature", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		request.AddCookie(&http.Cookie{Name: "csrf_token", Value: "party.hat"})
+		h.ServeHTTP(recorder, request)
+		if cookies := recorder.Result().Cookies(); len(cookies) != 1 || cookies[0].Value == "party.hat" {
+			t.Fatal("unexpected cookies", cookies)
+		}
+	})
+
+	t.Run("accepts unsafe requests with the masked token in the form or header", func(t *testing.T) {
+		cookie, token := getToken(t)
+
+		for _, method := range []string{http.MethodPost, http.MethodPut, http.MethodPatch} {
+			request := httptest.NewRequest(method, "/", strings.NewReader(url.Values{"csrf_token": {token}}.Encode()))
+			request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			request.AddCookie(cookie)
+			if code := serveCode(h, request); code != http.StatusOK {
+				t.Fatal("unexpected status code", method, code)
+			}
+		}
+
+		for _, method := range []string{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete} {
+			request := httptest.NewRequest(method, "/", nil)
+			request.Header.Set("X-CSRF-Token", token)
+			request.AddCookie(cookie)
+			if code := serveCode(h, request); code != http.StatusOK {
+				t.Fatal("unexpected status code", method, code)
+			}
+		}
+	})
+
+	t.Run("rejects unsafe requests without a matching, signed token", func(t *testing.T) {
+		cookie, token := getToken(t)
+		_, otherToken := getToken(t)
+		forged := "party.hat"
+
+		tests := []struct {
+			name   string
+			cookie string
+			header string
+		}{
+			{name: "no token", cookie: cookie.Value},
+			{name: "no cookie", header: token},
+			{name: "token of another client", cookie: cookie.Value, header: otherToken},
+			{name: "unmasked token", cookie: cookie.Value, header: cookie.Value},
+			{name: "truncated token", cookie: cookie.Value, header: token[:len(token)-1]},
+			{name: "forged cookie and token", cookie: forged, header: forged},
+		}
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				request := httptest.NewRequest(http.MethodPost, "/", nil)
+				if test.cookie != "" {
+					request.AddCookie(&http.Cookie{Name: "csrf_token", Value: test.cookie})
+				}
+				if test.header != "" {
+					request.Header.Set("X-CSRF-Token", test.header)
+				}
+				if code := serveCode(h, request); code != http.StatusForbidden {
+					t.Fatal("unexpected status code", code)
+				}
+			})
+		}
+	})
+
+	t.Run("sets the secure attribute on the cookie over TLS or if configured", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			secure   bool
+			https    bool
+			expected bool
+		}{
+			{name: "http", expected: false},
+			{name: "https", https: true, expected: true},
+			{name: "http with secure option", secure: true, expected: true},
+		}
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				h := ghttp.CSRF(key, ghttp.CSRFOptions{Secure: test.secure})(http.NotFoundHandler())
+				target := "http://example.com/"
+				if test.https {
+					target = "https://example.com/"
+				}
+				recorder := httptest.NewRecorder()
+				h.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+				cookies := recorder.Result().Cookies()
+				if len(cookies) != 1 || cookies[0].Secure != test.expected {
+					t.Fatal("unexpected cookies", cookies)
+				}
+			})
+		}
+	})
+
+	t.Run("panics on short keys", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r != "CSRF key must be at least 32 bytes long" {
+				t.Fatal("unexpected panic", r)
+			}
+		}()
+		ghttp.CSRF([]byte("short"), ghttp.CSRFOptions{})
+	})
+}
+
+func serveCode(h http.Handler, r *http.Request) int {
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, r)
+	return recorder.Code
+}
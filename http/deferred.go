@@ -0,0 +1,156 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+
+	g "maragu.dev/gomponents"
+	h "maragu.dev/gomponents/html"
+)
+
+// Deferred returns a [g.Node] for content that depends on slow data, so the rest of the page doesn't have to wait for it.
+//
+// When rendered by [Adapt], the fallback [g.Node] is rendered in place of the content, and f is called concurrently
+// with the rendering of the rest of the page. After the page is rendered and flushed to the client,
+// the content returned by f is streamed in the order it gets ready, each with a small inline script that swaps
+// it with the fallback. The content is rendered inside a template element, so it's not displayed before it's swapped in,
+// but scripts in it run when it is, like in the rest of the page.
+//
+// When rendered any other way, f is called and its result is rendered right away, without the fallback.
+//
+// The context given to f is the request context, which is cancelled when the response is done.
+// If f panics, the panic is passed on to the request goroutine when it's the content's turn to be streamed,
+// so it's handled by the server like a panic in any other handler.
+// The inline scripts get the CSP nonce from the request context, if any. See [CSP].
+func Deferred(fallback g.Node, f func(ctx context.Context) g.Node) g.Node {
+	return g.ContextFunc(func(ctx context.Context, w io.Writer) error {
+		d, ok := ctx.Value(deferrerContextKey{}).(*deferrer)
+		if !ok {
+			return g.RenderCtx(ctx, w, f(ctx))
+		}
+
+		id := d.start(ctx, f)
+		return g.RenderCtx(ctx, w, g.Group{
+			h.Template(h.ID(deferredID(id))),
+			fallback,
+			h.Template(h.ID(deferredID(id) + "-end")),
+		})
+	})
+}
+
+type deferrerContextKey struct{}
+
+// deferrer keeps track of [Deferred] content while rendering a response.
+type deferrer struct {
+	mu      sync.Mutex
+	id      int
+	pending int
+	results chan deferredResult
+	scripts bool
+}
+
+// deferredResult is the [g.Node] returned by the function of the [Deferred] content with the id,
+// or the value it panicked with, which is panicked with again on the goroutine that streams the content.
+type deferredResult struct {
+	id int
+	n  g.Node
+	p  interface{}
+}
+
+func newDeferrer() *deferrer {
+	return &deferrer{results: make(chan deferredResult)}
+}
+
+// start calling f concurrently, returning the id of the deferred content.
+func (d *deferrer) start(ctx context.Context, f func(ctx context.Context) g.Node) int {
+	d.mu.Lock()
+	d.id++
+	d.pending++
+	id := d.id
+	d.mu.Unlock()
+
+	go func() {
+		r := deferredResult{id: id}
+		// Recover, so a panic doesn't crash the server, but is passed on to the request goroutine in stream
+		func() {
+			defer func() {
+				r.p = recover()
+			}()
+			r.n = f(ctx)
+		}()
+
+		select {
+		case d.results <- r:
+		case <-ctx.Done():
+		}
+	}()
+
+	return id
+}
+
+// isPending reports whether any deferred content hasn't been streamed yet.
+func (d *deferrer) isPending() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.pending > 0
+}
+
+// stream deferred content to w as it gets ready, flushing after each, until there is no more content or ctx is done.
+// Content can itself contain [Deferred] content, which is streamed as well.
+// If there is no deferred content, nothing is written or flushed.
+func (d *deferrer) stream(ctx context.Context, w http.ResponseWriter) error {
+	if !d.isPending() {
+		return nil
+	}
+
+	for d.isPending() {
+		flush(w)
+
+		var r deferredResult
+		select {
+		case r = <-d.results:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if r.p != nil {
+			panic(r.p)
+		}
+
+		if err := g.RenderCtx(ctx, w, g.Group{
+			g.If(!d.scripts, h.Script(g.Raw(deferredScript))),
+			h.Template(h.ID(deferredID(r.id)+"-content"), r.n),
+			h.Script(g.Rawf("gomponentsDeferred(%v)", r.id)),
+		}); err != nil {
+			return err
+		}
+		d.scripts = true
+
+		d.mu.Lock()
+		d.pending--
+		d.mu.Unlock()
+	}
+	flush(w)
+	return nil
+}
+
+// deferredScript defines the function that swaps the fallback of the [Deferred] content with the given id,
+// which is between the start and end templates, with the content in the content template.
+const deferredScript = `function gomponentsDeferred(id){` +
+	`var s=document.getElementById("gomponents-deferred-"+id),e=document.getElementById("gomponents-deferred-"+id+"-end"),` +
+	`t=document.getElementById("gomponents-deferred-"+id+"-content");` +
+	`if(s&&e){while(s.nextSibling&&s.nextSibling!==e){s.nextSibling.remove()}e.replaceWith(t.content);s.remove()}` +
+	`t.remove()}`
+
+func deferredID(id int) string {
+	return "gomponents-deferred-" + strconv.Itoa(id)
+}
+
+// flush w if it's a [http.Flusher].
+func flush(w http.ResponseWriter) {
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
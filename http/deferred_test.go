@@ -0,0 +1,183 @@
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	g "maragu.dev/gomponents"
+	ghttp "maragu.dev/gomponents/http"
+	"maragu.dev/gomponents/internal/assert"
+)
+
+const deferredScript = `<script>function gomponentsDeferred(id){var s=document.getElementById("gomponents-deferred-"+id),e=document.getElementById("gomponents-deferred-"+id+"-end"),t=document.getElementById("gomponents-deferred-"+id+"-content");if(s&&e){while(s.nextSibling&&s.nextSibling!==e){s.nextSibling.remove()}e.replaceWith(t.content);s.remove()}t.remove()}</script>`
+
+func TestDeferred(t *testing.T) {
+	t.Run("renders the content right away outside of Adapt", func(t *testing.T) {
+		n := g.El("div", ghttp.Deferred(g.Text("Loading…"), func(ctx context.Context) g.Node {
+			return g.El("span", g.Text("Party hat"))
+		}))
+		assert.Equal(t, `<div><span>Party hat</span></div>`, n)
+	})
+
+	t.Run("renders the fallback and streams the content after the page in Adapt", func(t *testing.T) {
+		h := ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+			return g.El("div",
+				ghttp.Deferred(g.Text("Loading…"), func(ctx context.Context) g.Node {
+					return g.El("span", g.Text("Party hat"))
+				}),
+				g.El("p"),
+			), nil
+		})
+
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		expected := `<div><template id="gomponents-deferred-1"></template>Loading…<template id="gomponents-deferred-1-end"></template><p></p></div>` +
+			deferredScript +
+			`<template id="gomponents-deferred-1-content"><span>Party hat</span></template><script>gomponentsDeferred(1)</script>`
+		if body := recorder.Body.String(); body != expected {
+			t.Fatalf("expected\n%v\nbut got\n%v", expected, body)
+		}
+		if !recorder.Flushed {
+			t.Fatal("not flushed")
+		}
+	})
+
+	t.Run("renders the content right away in cached nodes", func(t *testing.T) {
+		c := g.NewLRUCacher(10)
+		h := ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+			return g.CacheNodeWith(c, "hat", 0, func() g.Node {
+				return ghttp.Deferred(g.Text("Loading…"), func(ctx context.Context) g.Node {
+					return g.El("span", g.Text("Party hat"))
+				})
+			}), nil
+		})
+
+		for i := 0; i < 2; i++ {
+			recorder := httptest.NewRecorder()
+			h.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+			if body := recorder.Body.String(); body != `<span>Party hat</span>` {
+				t.Fatal("unexpected body", body)
+			}
+		}
+	})
+
+	t.Run("streams content in the order it gets ready", func(t *testing.T) {
+		first := make(chan struct{})
+		h := ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+			return g.Group{
+				ghttp.Deferred(nil, func(ctx context.Context) g.Node {
+					<-first
+					return g.Text("slow")
+				}),
+				ghttp.Deferred(nil, func(ctx context.Context) g.Node {
+					defer close(first)
+					return g.Text("fast")
+				}),
+			}, nil
+		})
+
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		body := recorder.Body.String()
+		fast := strings.Index(body, `<template id="gomponents-deferred-2-content">fast</template><script>gomponentsDeferred(2)</script>`)
+		slow := strings.Index(body, `<template id="gomponents-deferred-1-content">slow</template><script>gomponentsDeferred(1)</script>`)
+		if fast < 0 || slow < 0 || fast > slow {
+			t.Fatal("unexpected body", body)
+		}
+		if strings.Count(body, "function gomponentsDeferred") != 1 {
+			t.Fatal("script not included exactly once", body)
+		}
+	})
+
+	t.Run("streams deferred content in deferred content", func(t *testing.T) {
+		h := ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+			return ghttp.Deferred(nil, func(ctx context.Context) g.Node {
+				return ghttp.Deferred(g.Text("inner fallback"), func(ctx context.Context) g.Node {
+					return g.Text("inner")
+				})
+			}), nil
+		})
+
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		body := recorder.Body.String()
+		if !strings.HasSuffix(body, `<template id="gomponents-deferred-2-content">inner</template><script>gomponentsDeferred(2)</script>`) {
+			t.Fatal("unexpected body", body)
+		}
+	})
+
+	t.Run("does not flush without deferred content", func(t *testing.T) {
+		h := ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+			return g.El("div"), nil
+		})
+
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+		if recorder.Flushed {
+			t.Fatal("flushed")
+		}
+	})
+
+	t.Run("stops streaming when the request context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		h := ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+			return g.Group{
+				ghttp.Deferred(g.Text("fallback"), func(ctx context.Context) g.Node {
+					defer close(done)
+					cancel()
+					<-ctx.Done()
+					return g.Text("never")
+				}),
+			}, nil
+		})
+
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+		<-done
+
+		if body := recorder.Body.String(); strings.Contains(body, "never") {
+			t.Fatal("unexpected body", body)
+		}
+	})
+
+	t.Run("panics on the serving goroutine if the content function panics", func(t *testing.T) {
+		h := ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+			return ghttp.Deferred(g.Text("fallback"), func(ctx context.Context) g.Node {
+				panic("no party")
+			}), nil
+		})
+
+		defer func() {
+			if r := recover(); r != "no party" {
+				t.Fatal("unexpected panic", r)
+			}
+		}()
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
+
+func ExampleDeferred() {
+	h := ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+		return g.El("main",
+			g.El("h1", g.Text("Dashboard")),
+			ghttp.Deferred(g.Text("Loading sales…"), func(ctx context.Context) g.Node {
+				// Fetch slow data here
+				return g.El("p", g.Text("Sales: 42 party hats"))
+			}),
+		), nil
+	})
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	_, _ = os.Stdout.WriteString(strings.Replace(recorder.Body.String(), deferredScript, "\n", 1))
+	// Output:
+	// <main><h1>Dashboard</h1><template id="gomponents-deferred-1"></template>Loading sales…<template id="gomponents-deferred-1-end"></template></main>
+	// <template id="gomponents-deferred-1-content"><p>Sales: 42 party hats</p></template><script>gomponentsDeferred(1)</script>
+}
@@ -0,0 +1,117 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	g "maragu.dev/gomponents"
+	"maragu.dev/gomponents/html"
+)
+
+// PreloadLink is a critical resource of a page, like a stylesheet, font, or script.
+// It Renders a <link rel="preload"> element, so put it in the head of the page.
+// With [Adapter.EarlyHints], the resource is also sent in a Link header with the response.
+// Return it from [Adapter.Preloads] as well to send it in a 103 Early Hints response before the [Handler] is called,
+// so the browser can start fetching it while the server is still working.
+//
+// See https://developer.mozilla.org/en-US/docs/Web/HTML/Attributes/rel/preload
+type PreloadLink struct {
+	// URL of the resource.
+	URL string
+
+	// As is the type of the resource, like "style", "font", "script", or "image".
+	As string
+
+	// Type is the optional MIME type of the resource, like "font/woff2".
+	Type string
+
+	// CrossOrigin is the optional CORS mode, "anonymous" or "use-credentials". Fonts always need it.
+	CrossOrigin string
+}
+
+// Render satisfies [g.Node].
+func (p PreloadLink) Render(w io.Writer) error {
+	return html.Link(
+		html.Rel("preload"),
+		html.Href(p.URL),
+		html.As(p.As),
+		g.If(p.Type != "", html.Type(p.Type)),
+		g.If(p.CrossOrigin != "", html.CrossOrigin(p.CrossOrigin)),
+	).Render(w)
+}
+
+// linkHeaderEscaper percent-encodes the characters that would end the URL in a Link header early.
+var linkHeaderEscaper = strings.NewReplacer("<", "%3C", ">", "%3E")
+
+// header value for the Link header, like `</app.css>; rel=preload; as=style`.
+func (p PreloadLink) header() string {
+	var b strings.Builder
+	b.WriteString("<" + linkHeaderEscaper.Replace(p.URL) + ">; rel=preload; as=" + quoteHeaderParam(p.As))
+	if p.Type != "" {
+		b.WriteString("; type=" + quoteHeaderParam(p.Type))
+	}
+	if p.CrossOrigin != "" {
+		b.WriteString("; crossorigin=" + quoteHeaderParam(p.CrossOrigin))
+	}
+	return b.String()
+}
+
+// quoteHeaderParam returns v as a header parameter value, quoted if it's not a token, like "font/woff2".
+func quoteHeaderParam(v string) string {
+	if v != "" && strings.Trim(v, "!#$%&'*+-.^_`|~0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ") == "" {
+		return v
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(v) + `"`
+}
+
+// findPreloads finds the [PreloadLink]-s in the [g.Node] tree n, outside of the body element.
+// Like [Fragment]-s, they're found through [g.ElementNode] and [g.Group] nodes, see [g.Walk].
+func findPreloads(n g.Node) []PreloadLink {
+	var preloads []PreloadLink
+	g.Inspect(n, func(n g.Node) bool {
+		switch n := n.(type) {
+		case PreloadLink:
+			preloads = append(preloads, n)
+		case g.ElementNode:
+			// Preload links belong in the head, so don't look through the whole page
+			return n.Name != "body"
+		}
+		return true
+	})
+	return preloads
+}
+
+// sendEarlyHints for the preloads, by adding them to the Link header and sending a 103 Early Hints response.
+// The Link header is kept for the final response.
+func sendEarlyHints(w http.ResponseWriter, preloads []PreloadLink) {
+	if len(preloads) == 0 {
+		return
+	}
+
+	for _, p := range preloads {
+		w.Header().Add("Link", p.header())
+	}
+	writeEarlyHints(w)
+}
+
+// addPreloadHeaders for the [PreloadLink]-s in n to the Link header of the final response,
+// except the ones already sent with [sendEarlyHints].
+func addPreloadHeaders(w http.ResponseWriter, n g.Node, sent []PreloadLink) {
+	for _, p := range findPreloads(n) {
+		if !containsPreload(sent, p) {
+			w.Header().Add("Link", p.header())
+			sent = append(sent, p)
+		}
+	}
+}
+
+// containsPreload reports whether p is in preloads.
+func containsPreload(preloads []PreloadLink, p PreloadLink) bool {
+	for _, q := range preloads {
+		if q == p {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,11 @@
+//go:build !go1.19
+
+package http
+
+import (
+	"net/http"
+)
+
+// writeEarlyHints does nothing, because net/http only supports informational responses since Go 1.19.
+// The Link header is still sent with the final response.
+func writeEarlyHints(http.ResponseWriter) {}
@@ -0,0 +1,12 @@
+//go:build go1.19
+
+package http
+
+import (
+	"net/http"
+)
+
+// writeEarlyHints sends a 103 Early Hints response with the current headers.
+func writeEarlyHints(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusEarlyHints)
+}
@@ -0,0 +1,172 @@
+//go:build go1.19
+
+package http_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	g "maragu.dev/gomponents"
+	ghttp "maragu.dev/gomponents/http"
+	"maragu.dev/gomponents/internal/assert"
+)
+
+func TestPreloadLink(t *testing.T) {
+	t.Run("renders a preload link element", func(t *testing.T) {
+		assert.Equal(t, `<link rel="preload" href="/app.css" as="style">`, ghttp.PreloadLink{URL: "/app.css", As: "style"})
+	})
+
+	t.Run("renders the type and crossorigin attributes if set", func(t *testing.T) {
+		assert.Equal(t, `<link rel="preload" href="/hat.woff2" as="font" type="font/woff2" crossorigin="anonymous">`,
+			ghttp.PreloadLink{URL: "/hat.woff2", As: "font", Type: "font/woff2", CrossOrigin: "anonymous"})
+	})
+}
+
+func TestAdapter_EarlyHints(t *testing.T) {
+	page := func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+		return g.El("html",
+			g.El("head",
+				ghttp.PreloadLink{URL: "/app.css", As: "style"},
+				g.Group{ghttp.PreloadLink{URL: "/hat.woff2", As: "font", Type: "font/woff2", CrossOrigin: "anonymous"}},
+			),
+			g.El("body", ghttp.PreloadLink{URL: "/ignored.js", As: "script"}),
+		), nil
+	}
+	expectedLinks := []string{
+		"</app.css>; rel=preload; as=style",
+		`</hat.woff2>; rel=preload; as=font; type="font/woff2"; crossorigin=anonymous`,
+	}
+
+	preloads := func(r *http.Request) []ghttp.PreloadLink {
+		return []ghttp.PreloadLink{{URL: "/app.css", As: "style"}}
+	}
+
+	t.Run("sends preloads in a 103 early hints response before calling the handler, and with the final response", func(t *testing.T) {
+		for _, buffered := range []bool{false, true} {
+			h := ghttp.Adapter{EarlyHints: true, Buffered: buffered, Preloads: preloads}.Adapt(func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+				if rw, ok := w.(*statusRecordingWriter); ok && (len(rw.codes) != 1 || rw.codes[0] != http.StatusEarlyHints) {
+					t.Fatal("unexpected status codes before calling the handler", rw.codes)
+				}
+				return page(w, r)
+			})
+
+			rw := &statusRecordingWriter{ResponseWriter: httptest.NewRecorder()}
+			h.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			code, body, hints, header := getWithEarlyHints(t, h)
+			if code != http.StatusOK {
+				t.Fatal("unexpected status code", code)
+			}
+			if !strings.HasPrefix(body, `<html><head><link rel="preload" href="/app.css" as="style">`) {
+				t.Fatal("unexpected body", body)
+			}
+			if len(hints) != 1 || strings.Join(hints[0]["Link"], "\n") != expectedLinks[0] {
+				t.Fatal("unexpected early hints", hints)
+			}
+			if strings.Join(header["Link"], "\n") != strings.Join(expectedLinks, "\n") {
+				t.Fatal("unexpected link header", header["Link"])
+			}
+		}
+	})
+
+	t.Run("adds preloads in the node to the link header of the final response, without early hints", func(t *testing.T) {
+		h := ghttp.Adapter{EarlyHints: true}.Adapt(page)
+		code, _, hints, header := getWithEarlyHints(t, h)
+		if code != http.StatusOK || len(hints) != 0 {
+			t.Fatal("unexpected response", code, hints)
+		}
+		if strings.Join(header["Link"], "\n") != strings.Join(expectedLinks, "\n") {
+			t.Fatal("unexpected link header", header["Link"])
+		}
+	})
+
+	t.Run("sends no early hints without preloads", func(t *testing.T) {
+		h := ghttp.Adapter{EarlyHints: true}.Adapt(func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+			return g.El("div"), nil
+		})
+		code, _, hints, header := getWithEarlyHints(t, h)
+		if code != http.StatusOK || len(hints) != 0 || header.Get("Link") != "" {
+			t.Fatal("unexpected response", code, hints, header)
+		}
+	})
+
+	t.Run("sends no early hints if not enabled or on errors", func(t *testing.T) {
+		for _, h := range []http.Handler{
+			ghttp.Adapter{}.Adapt(page),
+			ghttp.Adapter{Preloads: preloads}.Adapt(page),
+			ghttp.Adapter{EarlyHints: true}.Adapt(func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+				n, _ := page(w, r)
+				return n, statusCodeError{http.StatusNotFound}
+			}),
+		} {
+			_, _, hints, header := getWithEarlyHints(t, h)
+			if len(hints) != 0 || header.Get("Link") != "" {
+				t.Fatal("unexpected early hints", hints, header)
+			}
+		}
+	})
+
+	t.Run("escapes URLs and quotes parameters in the link header", func(t *testing.T) {
+		h := ghttp.Adapter{EarlyHints: true}.Adapt(func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+			return ghttp.PreloadLink{URL: "/a<b>.css", As: `st"yle`}, nil
+		})
+		_, _, _, header := getWithEarlyHints(t, h)
+		if link := header.Get("Link"); link != `</a%3Cb%3E.css>; rel=preload; as="st\"yle"` {
+			t.Fatal("unexpected link header", link)
+		}
+	})
+}
+
+// statusRecordingWriter records the status codes written to it.
+type statusRecordingWriter struct {
+	http.ResponseWriter
+	codes []int
+}
+
+func (w *statusRecordingWriter) WriteHeader(code int) {
+	w.codes = append(w.codes, code)
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// getWithEarlyHints from a test server with the handler, returning the informational response headers as well.
+func getWithEarlyHints(t *testing.T, h http.Handler) (int, string, []textproto.MIMEHeader, http.Header) {
+	t.Helper()
+
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	var hints []textproto.MIMEHeader
+	trace := &httptrace.ClientTrace{
+		Got1xxResponse: func(code int, header textproto.MIMEHeader) error {
+			if code == http.StatusEarlyHints {
+				hints = append(hints, header)
+			}
+			return nil
+		},
+	}
+
+	request, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	request = request.WithContext(httptrace.WithClientTrace(request.Context(), trace))
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return response.StatusCode, string(body), hints, response.Header
+}
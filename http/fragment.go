@@ -0,0 +1,76 @@
+package http
+
+import (
+	"io"
+	"net/http"
+
+	g "maragu.dev/gomponents"
+)
+
+// Fragment marks the [g.Node] n as a named fragment of a page.
+// [Adapt], [AdaptBuffered], and [Adapter] render just the fragment instead of the whole page when it's requested,
+// so the same [Handler] can serve both the page and its parts. A fragment is requested:
+//   - with the query parameter "fragment", like "/products?fragment=cart", or
+//   - with htmx, where the HX-Target header is the name, so name fragments after the IDs of the elements htmx swaps.
+//     Boosted and history restore requests from htmx get the whole page.
+//
+// Because the htmx headers change the response, they're added to the Vary header of responses to requests
+// without the query parameter, so caches don't serve a fragment as the whole page or the other way around.
+//
+// If the requested fragment isn't found, the whole page is rendered.
+// Fragments are found through [g.ElementNode] and [g.Group] nodes, see [g.Walk],
+// so they can't be inside nodes like [g.NodeFunc]. Otherwise, Fragment renders just like n.
+func Fragment(name string, n g.Node) g.Node {
+	return g.Group{fragmentMarker(name), n}
+}
+
+// fragmentMarker marks the following [g.Node] in a [g.Group] as a [Fragment] with the name.
+// It's an attribute that renders nothing, so it doesn't change how elements it's in are rendered.
+type fragmentMarker string
+
+// Render satisfies [g.Node].
+func (fragmentMarker) Render(io.Writer) error {
+	return nil
+}
+
+// Type satisfies nodeTypeDescriber.
+func (fragmentMarker) Type() g.NodeType {
+	return g.AttributeType
+}
+
+// fragmentHeaders are the request headers that [requestedFragment] looks at, for the Vary header.
+const fragmentHeaders = "HX-Request, HX-Target, HX-Boosted, HX-History-Restore-Request"
+
+// requestedFragment name in the request, or an empty string if no fragment is requested.
+// If the name isn't in the query parameter, the request headers are looked at, so they're added to the Vary header.
+func requestedFragment(w http.ResponseWriter, r *http.Request) string {
+	if name := r.URL.Query().Get("fragment"); name != "" {
+		return name
+	}
+
+	w.Header().Add("Vary", fragmentHeaders)
+	if r.Header.Get("HX-Request") == "true" && r.Header.Get("HX-Boosted") != "true" &&
+		r.Header.Get("HX-History-Restore-Request") != "true" {
+		return r.Header.Get("HX-Target")
+	}
+	return ""
+}
+
+// findFragment with the name in the tree rooted at n, reporting whether it was found.
+func findFragment(n g.Node, name string) (g.Node, bool) {
+	var fragment g.Node
+	var found bool
+	g.Inspect(n, func(n g.Node) bool {
+		if found {
+			return false
+		}
+		if group, ok := n.(g.Group); ok && len(group) == 2 {
+			if marker, ok := group[0].(fragmentMarker); ok && string(marker) == name {
+				fragment, found = group[1], true
+				return false
+			}
+		}
+		return true
+	})
+	return fragment, found
+}
@@ -0,0 +1,150 @@
+package http_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	g "maragu.dev/gomponents"
+	ghttp "maragu.dev/gomponents/http"
+)
+
+func TestFragment(t *testing.T) {
+	page := func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+		return g.El("main",
+			ghttp.Fragment("cart", g.El("div", g.Attr("id", "cart"),
+				g.Text("Cart"),
+				ghttp.Fragment("count", g.El("span", g.Text("3"))),
+			)),
+			g.El("p", g.Text("Products")),
+		), nil
+	}
+
+	tests := []struct {
+		name     string
+		target   string
+		headers  map[string]string
+		expected string
+	}{
+		{
+			name:     "renders the whole page without a requested fragment",
+			target:   "/",
+			expected: `<main><div id="cart">Cart<span>3</span></div><p>Products</p></main>`,
+		},
+		{
+			name:     "renders the fragment from the query parameter",
+			target:   "/?fragment=cart",
+			expected: `<div id="cart">Cart<span>3</span></div>`,
+		},
+		{
+			name:     "renders nested fragments",
+			target:   "/?fragment=count",
+			expected: `<span>3</span>`,
+		},
+		{
+			name:     "renders the whole page if the fragment isn't found",
+			target:   "/?fragment=hat",
+			expected: `<main><div id="cart">Cart<span>3</span></div><p>Products</p></main>`,
+		},
+		{
+			name:     "renders the fragment from the htmx target",
+			target:   "/",
+			headers:  map[string]string{"HX-Request": "true", "HX-Target": "cart"},
+			expected: `<div id="cart">Cart<span>3</span></div>`,
+		},
+		{
+			name:     "renders the whole page for htmx requests without a target",
+			target:   "/",
+			headers:  map[string]string{"HX-Request": "true"},
+			expected: `<main><div id="cart">Cart<span>3</span></div><p>Products</p></main>`,
+		},
+		{
+			name:     "renders the whole page for boosted htmx requests",
+			target:   "/",
+			headers:  map[string]string{"HX-Request": "true", "HX-Target": "cart", "HX-Boosted": "true"},
+			expected: `<main><div id="cart">Cart<span>3</span></div><p>Products</p></main>`,
+		},
+		{
+			name:     "renders the whole page for htmx history restore requests",
+			target:   "/",
+			headers:  map[string]string{"HX-Request": "true", "HX-Target": "cart", "HX-History-Restore-Request": "true"},
+			expected: `<main><div id="cart">Cart<span>3</span></div><p>Products</p></main>`,
+		},
+		{
+			name:     "ignores the htmx target without the htmx request header",
+			target:   "/",
+			headers:  map[string]string{"HX-Target": "cart"},
+			expected: `<main><div id="cart">Cart<span>3</span></div><p>Products</p></main>`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, h := range []http.Handler{ghttp.Adapt(page), ghttp.AdaptBuffered(page)} {
+				recorder := httptest.NewRecorder()
+				request := httptest.NewRequest(http.MethodGet, test.target, nil)
+				for k, v := range test.headers {
+					request.Header.Set(k, v)
+				}
+				h.ServeHTTP(recorder, request)
+				if body := recorder.Body.String(); body != test.expected {
+					t.Fatalf("expected %v but got %v", test.expected, body)
+				}
+			}
+		})
+	}
+
+	t.Run("varies on the htmx headers unless the fragment is in the query parameter", func(t *testing.T) {
+		h := ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+			w.Header().Set("Vary", "Accept-Language")
+			return page(w, r)
+		})
+
+		for target, expected := range map[string][]string{
+			"/":               {"Accept-Language", "HX-Request, HX-Target, HX-Boosted, HX-History-Restore-Request"},
+			"/?fragment=cart": {"Accept-Language"},
+		} {
+			recorder := httptest.NewRecorder()
+			h.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+			if vary := recorder.Header().Values("Vary"); fmt.Sprint(vary) != fmt.Sprint(expected) {
+				t.Fatal("unexpected vary header", target, vary)
+			}
+		}
+	})
+
+	t.Run("renders nothing for nil node", func(t *testing.T) {
+		h := ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+			return nil, nil
+		})
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/?fragment=cart", nil))
+		if body := recorder.Body.String(); body != "" {
+			t.Fatal("body is", body)
+		}
+	})
+
+	t.Run("renders like the node outside of Adapt", func(t *testing.T) {
+		n := g.El("div", ghttp.Fragment("hat", g.El("span")), ghttp.Fragment("class", g.Attr("class", "party")))
+		if s := n.(g.ElementNode).String(); s != `<div class="party"><span></span></div>` {
+			t.Fatal("unexpected output", s)
+		}
+	})
+}
+
+func ExampleFragment() {
+	h := ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+		return g.El("main",
+			g.El("h1", g.Text("Party hats")),
+			ghttp.Fragment("cart", g.El("div", g.Attr("id", "cart"), g.Text("3 hats"))),
+		), nil
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("HX-Request", "true")
+	request.Header.Set("HX-Target", "cart")
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, request)
+	fmt.Println(recorder.Body.String())
+	// Output: <div id="cart">3 hats</div>
+}
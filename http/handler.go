@@ -2,7 +2,12 @@
 package http
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"net/http"
+	"strconv"
+	"sync"
 
 	g "maragu.dev/gomponents"
 )
@@ -19,24 +24,228 @@ type errorWithStatusCode interface {
 // The returned [g.Node] is rendered to the [http.ResponseWriter], in both normal and error cases.
 // If the [Handler] returns an error, and it implements a "StatusCode() int" method, that HTTP status code is sent
 // in the response header. Otherwise, the status code [http.StatusInternalServerError] (500) is used.
+// Use [Adapter.StatusCodes] for status codes of wrapped errors.
+// The [g.Node] is rendered with the request context, see [g.RenderCtx].
+// If the request context is done, for example because the client went away, rendering stops.
+// [Deferred] content is streamed after the rest of the [g.Node] is rendered.
+// If the request asks for a [Fragment] of the [g.Node], only that is rendered.
+//
+// The [g.Node] is streamed to the client while it's rendered, so if rendering fails halfway,
+// the client gets the first part of the page followed by an error message. See [AdaptBuffered] for an alternative.
+// See [Adapter] for more options, like the Content-Type header and error pages.
 func Adapt(h Handler) http.HandlerFunc {
+	return Adapter{}.adapt(h)
+}
+
+// AdaptBuffered is like [Adapt], but renders the [g.Node] into a buffer before sending anything to the client.
+// If rendering fails, the client gets a clean error response with status code [http.StatusInternalServerError] (500)
+// instead of a partial page. Otherwise, the response has an accurate Content-Length header.
+// Because nothing is streamed, [Deferred] content is rendered in place, without the fallback.
+// Use it for pages that are fast to render, and [Adapt] for large or slow pages that benefit from streaming.
+func AdaptBuffered(h Handler) http.HandlerFunc {
+	return Adapter{Buffered: true}.adapt(h)
+}
+
+// Adapter adapts [Handler]-s to [http.HandlerFunc]-s like [Adapt] and [AdaptBuffered], with options.
+// Create it once with the options shared by all handlers, and call [Adapter.Adapt] for each handler.
+type Adapter struct {
+	// ContentType header value, set if the [Handler] didn't set it. Defaults to "text/html; charset=utf-8".
+	ContentType string
+
+	// Buffered renders into a buffer before sending anything to the client, like [AdaptBuffered].
+	Buffered bool
+
+	// ErrorHandler is called if the [Handler] returns an error, and the returned [g.Node] is rendered
+	// instead of the one returned by the [Handler], for example a branded error page.
+	// If Buffered is true, it's also called if rendering fails, and the returned [g.Node] is rendered with
+	// status code [http.StatusInternalServerError] (500).
+	ErrorHandler func(w http.ResponseWriter, r *http.Request, err error) g.Node
+
+	// Log is called with errors returned by the [Handler] and rendering errors.
+	// See [SlogLogger] for logging with [log/slog].
+	Log func(r *http.Request, err error)
+
+	// StatusCodes maps errors to HTTP status codes, checked in order before the "StatusCode() int" method.
+	// Unlike the method, which is only checked on the returned error itself, they can match wrapped errors,
+	// see [StatusCodeAs] and [StatusCodeIs].
+	StatusCodes []func(err error) (int, bool)
+
+	// EarlyHints sends the [PreloadLink]-s from Preloads in Link headers, in a 103 Early Hints response before
+	// the [Handler] is called, and with the final response. The [PreloadLink]-s of the returned [g.Node] outside
+	// the body element are added to the Link header of the final response, but it's too late for early hints then.
+	// Requires Go 1.19 or later for the 103 Early Hints response.
+	EarlyHints bool
+
+	// Preloads returns the [PreloadLink]-s for the request, like the stylesheets and fonts shared by all pages,
+	// which are sent in a 103 Early Hints response if EarlyHints is true.
+	// They're sent even if the [Handler] then returns an error, so only return resources that are always needed.
+	Preloads func(r *http.Request) []PreloadLink
+}
+
+// Adapt a [Handler] to a [http.HandlerFunc], like [Adapt] and [AdaptBuffered], but with the options of the [Adapter].
+func (a Adapter) Adapt(h Handler) http.HandlerFunc {
+	if a.ContentType == "" {
+		a.ContentType = "text/html; charset=utf-8"
+	}
+	return a.adapt(h)
+}
+
+func (a Adapter) adapt(h Handler) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		var d *deferrer
+		if !a.Buffered {
+			d = newDeferrer()
+			ctx = context.WithValue(ctx, deferrerContextKey{}, d)
+		}
+		r = r.WithContext(ctx)
+
+		var preloads []PreloadLink
+		if a.EarlyHints && a.Preloads != nil {
+			preloads = a.Preloads(r)
+			sendEarlyHints(w, preloads)
+		}
+
 		n, err := h(w, r)
+		status := http.StatusOK
 		if err != nil {
-			switch v := err.(type) {
-			case errorWithStatusCode:
-				w.WriteHeader(v.StatusCode())
-			default:
-				w.WriteHeader(http.StatusInternalServerError)
+			a.log(r, err)
+			status = a.statusCode(err)
+			if a.ErrorHandler != nil {
+				n = a.ErrorHandler(w, r, err)
+			}
+		}
+
+		if name := requestedFragment(w, r); name != "" {
+			if fragment, ok := findFragment(n, name); ok {
+				n = fragment
 			}
 		}
 
-		if n == nil {
-			return
+		if a.EarlyHints && err == nil {
+			addPreloadHeaders(w, n, preloads)
+		}
+
+		if n != nil && a.ContentType != "" && w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", a.ContentType)
 		}
 
-		if err := n.Render(w); err != nil {
+		if a.Buffered {
+			err = renderBuffered(w, r, n, status)
+		} else {
+			err = renderStreamed(w, r, n, status, d)
+		}
+		if err != nil {
+			// There's no one to send the error to if the request context is done
+			if r.Context().Err() != nil {
+				return
+			}
+			a.log(r, err)
+			if a.Buffered && a.ErrorHandler != nil {
+				if renderBuffered(w, r, a.ErrorHandler(w, r, err), http.StatusInternalServerError) == nil {
+					return
+				}
+			}
 			http.Error(w, "error rendering node: "+err.Error(), http.StatusInternalServerError)
 		}
 	}
 }
+
+func (a Adapter) log(r *http.Request, err error) {
+	if a.Log != nil {
+		a.Log(r, err)
+	}
+}
+
+// statusCode for the error, from StatusCodes, the "StatusCode() int" method, or [http.StatusInternalServerError].
+func (a Adapter) statusCode(err error) int {
+	for _, f := range a.StatusCodes {
+		if code, ok := f(err); ok {
+			return code
+		}
+	}
+
+	if statusCodeErr, ok := err.(errorWithStatusCode); ok {
+		return statusCodeErr.StatusCode()
+	}
+	return http.StatusInternalServerError
+}
+
+// StatusCodeAs returns a function for [Adapter.StatusCodes] that maps errors to the status code,
+// if they match the error type T with [errors.As].
+func StatusCodeAs[T error](code int) func(err error) (int, bool) {
+	return func(err error) (int, bool) {
+		var target T
+		if errors.As(err, &target) {
+			return code, true
+		}
+		return 0, false
+	}
+}
+
+// StatusCodeIs returns a function for [Adapter.StatusCodes] that maps errors to the status code,
+// if they match the target error with [errors.Is], like [database/sql.ErrNoRows] to [http.StatusNotFound].
+func StatusCodeIs(target error, code int) func(err error) (int, bool) {
+	return func(err error) (int, bool) {
+		if errors.Is(err, target) {
+			return code, true
+		}
+		return 0, false
+	}
+}
+
+// renderStreamed n directly to w, followed by the [Deferred] content in d.
+func renderStreamed(w http.ResponseWriter, r *http.Request, n g.Node, status int, d *deferrer) error {
+	if status != http.StatusOK {
+		w.WriteHeader(status)
+	}
+
+	if n == nil {
+		return nil
+	}
+
+	if err := g.RenderCtx(r.Context(), w, n); err != nil {
+		return err
+	}
+	return d.stream(r.Context(), w)
+}
+
+// maxPooledBufferSize is the maximum capacity of buffers that are put back in the pool,
+// so a single huge page doesn't keep a lot of memory around.
+const maxPooledBufferSize = 1 << 20
+
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// renderBuffered n into a pooled buffer, and then to w with a Content-Length header.
+// If rendering fails, nothing is written. Like in renderStreamed, nothing but the status is written for a nil node,
+// so the handler can write the response itself, like with [http.Redirect].
+func renderBuffered(w http.ResponseWriter, r *http.Request, n g.Node, status int) error {
+	if n == nil {
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+		}
+		return nil
+	}
+
+	b := bufferPool.Get().(*bytes.Buffer)
+	defer func() {
+		if b.Cap() <= maxPooledBufferSize {
+			b.Reset()
+			bufferPool.Put(b)
+		}
+	}()
+
+	if err := g.RenderCtx(r.Context(), b, n); err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Length", strconv.Itoa(b.Len()))
+	w.WriteHeader(status)
+	_, _ = w.Write(b.Bytes()) // The client is gone if writing fails, so there's no one to tell
+	return nil
+}
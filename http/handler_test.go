@@ -1,10 +1,14 @@
 package http_test
 
 import (
+	"context"
+	"database/sql"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	g "maragu.dev/gomponents"
@@ -64,6 +68,51 @@ func TestAdapt(t *testing.T) {
 		}
 	})
 
+	t.Run("errors with 500 if only a wrapped error implements StatusCode method", func(t *testing.T) {
+		h := ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+			return g.El("div"), fmt.Errorf("no party: %w", statusCodeError{http.StatusTeapot})
+		})
+		code, body := get(t, h)
+		if code != http.StatusInternalServerError {
+			t.Fatal("status code is", code)
+		}
+		if body != "<div></div>" {
+			t.Fatal(`body is`, body)
+		}
+	})
+
+	t.Run("renders the node with the request context", func(t *testing.T) {
+		h := ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+			return g.El("div", g.ContextFunc(func(ctx context.Context, w io.Writer) error {
+				_, err := io.WriteString(w, ctx.Value(contextKey("hat")).(string))
+				return err
+			})), nil
+		})
+
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		request = request.WithContext(context.WithValue(request.Context(), contextKey("hat"), "party"))
+		h.ServeHTTP(recorder, request)
+		if body := recorder.Body.String(); body != "<div>party</div>" {
+			t.Fatal("body is", body)
+		}
+	})
+
+	t.Run("stops rendering and writes nothing more if the request context is done", func(t *testing.T) {
+		h := ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+			return g.El("div"), nil
+		})
+
+		recorder := httptest.NewRecorder()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		request := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+		h.ServeHTTP(recorder, request)
+		if body := recorder.Body.String(); body != "" {
+			t.Fatal("body is", body)
+		}
+	})
+
 	t.Run("errors with 500 if other error and renders node", func(t *testing.T) {
 		h := ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
 			return g.El("div"), errors.New("")
@@ -78,6 +127,271 @@ func TestAdapt(t *testing.T) {
 	})
 }
 
+func TestAdaptBuffered(t *testing.T) {
+	t.Run("renders a node to the response writer with a content length", func(t *testing.T) {
+		h := ghttp.AdaptBuffered(func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+			return g.El("div", g.Text("party")), nil
+		})
+
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+		if recorder.Code != http.StatusOK {
+			t.Fatal("status code is", recorder.Code)
+		}
+		if body := recorder.Body.String(); body != "<div>party</div>" {
+			t.Fatal("body is", body)
+		}
+		if contentLength := recorder.Header().Get("Content-Length"); contentLength != "16" {
+			t.Fatal("content length is", contentLength)
+		}
+	})
+
+	t.Run("renders nothing when returning nil node", func(t *testing.T) {
+		h := ghttp.AdaptBuffered(func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+			return nil, nil
+		})
+		code, body := get(t, h)
+		if code != http.StatusOK {
+			t.Fatal("status code is", code)
+		}
+		if body != "" {
+			t.Fatal(`body is`, body)
+		}
+	})
+
+	t.Run("writes no headers when returning nil node, so the handler can redirect", func(t *testing.T) {
+		h := ghttp.AdaptBuffered(func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+			http.Redirect(w, r, "/party", http.StatusFound)
+			return nil, nil
+		})
+		w := &headerCountingWriter{ResponseRecorder: httptest.NewRecorder()}
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		h.ServeHTTP(w, r)
+		if w.writeHeaderCalls != 1 {
+			t.Fatal("write header calls are", w.writeHeaderCalls)
+		}
+		if w.Code != http.StatusFound {
+			t.Fatal("status code is", w.Code)
+		}
+		if location := w.Header().Get("Location"); location != "/party" {
+			t.Fatal("location is", location)
+		}
+	})
+
+	t.Run("errors with 500 and no partial page if node cannot render", func(t *testing.T) {
+		h := ghttp.AdaptBuffered(func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+			return g.El("div", g.El("p"), erroringNode{}), nil
+		})
+		code, body := get(t, h)
+		if code != http.StatusInternalServerError {
+			t.Fatal("status code is", code)
+		}
+		if body != "error rendering node: don't want to\n" {
+			t.Fatal(`body is`, body)
+		}
+	})
+
+	t.Run("errors with status code if error implements StatusCode method and renders node", func(t *testing.T) {
+		h := ghttp.AdaptBuffered(func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+			return g.El("div"), statusCodeError{http.StatusTeapot}
+		})
+		code, body := get(t, h)
+		if code != http.StatusTeapot {
+			t.Fatal("status code is", code)
+		}
+		if body != "<div></div>" {
+			t.Fatal(`body is`, body)
+		}
+	})
+
+	t.Run("renders large pages", func(t *testing.T) {
+		text := strings.Repeat("party hat ", 200_000)
+		h := ghttp.AdaptBuffered(func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+			return g.Text(text), nil
+		})
+		for i := 0; i < 2; i++ {
+			if _, body := get(t, h); body != text {
+				t.Fatal("unexpected body length", len(body))
+			}
+		}
+	})
+
+	t.Run("renders deferred content in place", func(t *testing.T) {
+		h := ghttp.AdaptBuffered(func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+			return g.El("div", ghttp.Deferred(g.Text("Loading…"), func(ctx context.Context) g.Node {
+				return g.Text("party")
+			})), nil
+		})
+		_, body := get(t, h)
+		if body != "<div>party</div>" {
+			t.Fatal(`body is`, body)
+		}
+	})
+
+	t.Run("writes nothing if the request context is done", func(t *testing.T) {
+		h := ghttp.AdaptBuffered(func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+			return g.El("div"), nil
+		})
+
+		recorder := httptest.NewRecorder()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		h.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+		if body := recorder.Body.String(); body != "" {
+			t.Fatal("body is", body)
+		}
+	})
+}
+
+func TestAdapter(t *testing.T) {
+	t.Run("sets the default content type", func(t *testing.T) {
+		h := ghttp.Adapter{}.Adapt(func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+			return g.El("div"), nil
+		})
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+		if contentType := recorder.Header().Get("Content-Type"); contentType != "text/html; charset=utf-8" {
+			t.Fatal("content type is", contentType)
+		}
+	})
+
+	t.Run("sets the given content type, but not if the handler did", func(t *testing.T) {
+		a := ghttp.Adapter{ContentType: "image/svg+xml"}
+		h := a.Adapt(func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+			if r.URL.Path == "/custom" {
+				w.Header().Set("Content-Type", "text/plain")
+			}
+			return g.El("svg"), nil
+		})
+		for path, expected := range map[string]string{"/": "image/svg+xml", "/custom": "text/plain"} {
+			recorder := httptest.NewRecorder()
+			h.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+			if contentType := recorder.Header().Get("Content-Type"); contentType != expected {
+				t.Fatal("content type is", contentType)
+			}
+		}
+	})
+
+	t.Run("does not set the content type for nil nodes", func(t *testing.T) {
+		h := ghttp.Adapter{}.Adapt(func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+			return nil, nil
+		})
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+		if contentType := recorder.Header().Get("Content-Type"); contentType != "" {
+			t.Fatal("content type is", contentType)
+		}
+	})
+
+	t.Run("renders the error page from the error handler and logs the error", func(t *testing.T) {
+		var logged []error
+		a := ghttp.Adapter{
+			ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) g.Node {
+				return g.El("h1", g.Text(err.Error()))
+			},
+			Log: func(r *http.Request, err error) {
+				logged = append(logged, err)
+			},
+		}
+		h := a.Adapt(func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+			return g.El("div"), statusCodeError{http.StatusNotFound}
+		})
+		code, body := get(t, h)
+		if code != http.StatusNotFound {
+			t.Fatal("status code is", code)
+		}
+		if body != "<h1>Not Found</h1>" {
+			t.Fatal("body is", body)
+		}
+		if len(logged) != 1 {
+			t.Fatal("logged", logged)
+		}
+	})
+
+	t.Run("renders the error page on render errors when buffered", func(t *testing.T) {
+		var logged []error
+		a := ghttp.Adapter{
+			Buffered: true,
+			ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) g.Node {
+				return g.El("h1", g.Text("Oops"))
+			},
+			Log: func(r *http.Request, err error) {
+				logged = append(logged, err)
+			},
+		}
+		h := a.Adapt(func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+			return g.El("div", erroringNode{}), nil
+		})
+		code, body := get(t, h)
+		if code != http.StatusInternalServerError {
+			t.Fatal("status code is", code)
+		}
+		if body != "<h1>Oops</h1>" {
+			t.Fatal("body is", body)
+		}
+		if len(logged) != 1 || logged[0].Error() != "don't want to" {
+			t.Fatal("logged", logged)
+		}
+	})
+
+	t.Run("errors with 500 if the error page cannot render either", func(t *testing.T) {
+		a := ghttp.Adapter{
+			Buffered: true,
+			ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) g.Node {
+				return erroringNode{}
+			},
+		}
+		h := a.Adapt(func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+			return erroringNode{}, nil
+		})
+		code, body := get(t, h)
+		if code != http.StatusInternalServerError {
+			t.Fatal("status code is", code)
+		}
+		if body != "error rendering node: don't want to\n" {
+			t.Fatal("body is", body)
+		}
+	})
+
+	t.Run("maps errors to status codes", func(t *testing.T) {
+		errNotFound := errors.New("not found")
+		a := ghttp.Adapter{
+			StatusCodes: []func(error) (int, bool){
+				ghttp.StatusCodeIs(errNotFound, http.StatusNotFound),
+				ghttp.StatusCodeAs[*validationError](http.StatusBadRequest),
+			},
+		}
+
+		tests := []struct {
+			err      error
+			expected int
+		}{
+			{err: fmt.Errorf("wrapped: %w", errNotFound), expected: http.StatusNotFound},
+			{err: fmt.Errorf("wrapped: %w", &validationError{}), expected: http.StatusBadRequest},
+			{err: statusCodeError{http.StatusTeapot}, expected: http.StatusTeapot},
+			{err: errors.New("oh no"), expected: http.StatusInternalServerError},
+		}
+		for _, test := range tests {
+			t.Run(test.err.Error(), func(t *testing.T) {
+				h := a.Adapt(func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+					return g.El("div"), test.err
+				})
+				if code, _ := get(t, h); code != test.expected {
+					t.Fatal("status code is", code)
+				}
+			})
+		}
+	})
+}
+
+type validationError struct{}
+
+func (e *validationError) Error() string {
+	return "invalid"
+}
+
+type contextKey string
+
 type erroringNode struct{}
 
 func (n erroringNode) Render(io.Writer) error {
@@ -96,6 +410,17 @@ func (e statusCodeError) StatusCode() int {
 	return e.code
 }
 
+// headerCountingWriter is a [httptest.ResponseRecorder] that counts the calls to WriteHeader.
+type headerCountingWriter struct {
+	*httptest.ResponseRecorder
+	writeHeaderCalls int
+}
+
+func (w *headerCountingWriter) WriteHeader(code int) {
+	w.writeHeaderCalls++
+	w.ResponseRecorder.WriteHeader(code)
+}
+
 func get(t *testing.T, h http.Handler) (int, string) {
 	t.Helper()
 
@@ -120,3 +445,28 @@ func ExampleAdapt() {
 	mux := http.NewServeMux()
 	mux.Handle("/", h)
 }
+
+func ExampleAdapter() {
+	a := ghttp.Adapter{
+		Buffered: true,
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) g.Node {
+			return g.El("h1", g.Text("Something went wrong"))
+		},
+		StatusCodes: []func(error) (int, bool){
+			ghttp.StatusCodeIs(sql.ErrNoRows, http.StatusNotFound),
+		},
+	}
+	h := a.Adapt(func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+		return g.El("div"), nil
+	})
+	mux := http.NewServeMux()
+	mux.Handle("/", h)
+}
+
+func ExampleAdaptBuffered() {
+	h := ghttp.AdaptBuffered(func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+		return g.El("div"), nil
+	})
+	mux := http.NewServeMux()
+	mux.Handle("/", h)
+}
@@ -0,0 +1,23 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+)
+
+// HXRedirect makes htmx redirect the browser to the URL with a full page load, by setting the HX-Redirect response header.
+func HXRedirect(w http.ResponseWriter, url string) {
+	w.Header().Set("HX-Redirect", url)
+}
+
+// HXTrigger makes htmx trigger the named events on the target element,
+// by setting the HX-Trigger response header to the comma-separated event names.
+func HXTrigger(w http.ResponseWriter, events ...string) {
+	w.Header().Set("HX-Trigger", strings.Join(events, ", "))
+}
+
+// HXReswap overrides how htmx swaps the response, like "outerHTML" or "beforeend scroll:bottom",
+// by setting the HX-Reswap response header. See the Swap constants in the x/htmx package.
+func HXReswap(w http.ResponseWriter, swap string) {
+	w.Header().Set("HX-Reswap", swap)
+}
@@ -0,0 +1,37 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	g "maragu.dev/gomponents"
+	ghttp "maragu.dev/gomponents/http"
+)
+
+func TestHTMXHeaders(t *testing.T) {
+	tests := []struct {
+		name     string
+		set      func(w http.ResponseWriter)
+		header   string
+		expected string
+	}{
+		{name: "HXRedirect", set: func(w http.ResponseWriter) { ghttp.HXRedirect(w, "/hats") }, header: "HX-Redirect", expected: "/hats"},
+		{name: "HXTrigger", set: func(w http.ResponseWriter) { ghttp.HXTrigger(w, "cartUpdated", "partyStarted") }, header: "HX-Trigger", expected: "cartUpdated, partyStarted"},
+		{name: "HXReswap", set: func(w http.ResponseWriter) { ghttp.HXReswap(w, "outerHTML") }, header: "HX-Reswap", expected: "outerHTML"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := ghttp.Adapt(func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+				test.set(w)
+				return g.El("div"), nil
+			})
+			recorder := httptest.NewRecorder()
+			h.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", nil))
+			if v := recorder.Header().Get(test.header); v != test.expected {
+				t.Fatal("unexpected header value", v)
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+//go:build go1.21
+
+package http
+
+import (
+	"log/slog"
+	"net/http"
+)
+
+// SlogLogger returns a function for [Adapter.Log] that logs errors at the error level with the given [slog.Logger],
+// with the request context, method, and path.
+func SlogLogger(l *slog.Logger) func(r *http.Request, err error) {
+	return func(r *http.Request, err error) {
+		l.ErrorContext(r.Context(), "Error handling request", "method", r.Method, "path", r.URL.Path, "error", err)
+	}
+}
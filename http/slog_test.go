@@ -0,0 +1,30 @@
+//go:build go1.21
+
+package http_test
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	g "maragu.dev/gomponents"
+	ghttp "maragu.dev/gomponents/http"
+)
+
+func TestSlogLogger(t *testing.T) {
+	t.Run("logs errors with the request method and path", func(t *testing.T) {
+		var b bytes.Buffer
+		a := ghttp.Adapter{Log: ghttp.SlogLogger(slog.New(slog.NewTextHandler(&b, nil)))}
+		h := a.Adapt(func(w http.ResponseWriter, r *http.Request) (g.Node, error) {
+			return nil, errors.New("oh no")
+		})
+		_, _ = get(t, h)
+
+		if !strings.Contains(b.String(), `level=ERROR msg="Error handling request" method=GET path=/ error="oh no"`) {
+			t.Fatal("unexpected log", b.String())
+		}
+	})
+}
@@ -0,0 +1,113 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+
+	g "maragu.dev/gomponents"
+)
+
+// Event sent by an [SSEHandler], with the [g.Node] rendered as the event data.
+// Name and ID are optional, and newlines in them are removed.
+type Event struct {
+	Name string
+	ID   string
+	Node g.Node
+}
+
+// SSEHandler sends events on the channel until it returns.
+// It must stop sending when the request context is done, for example with a select statement.
+type SSEHandler = func(r *http.Request, events chan<- Event)
+
+// SSE adapts an [SSEHandler] to a [http.HandlerFunc] that streams server-sent events,
+// for example for live-updating pages with the same components used to render them.
+// The response has the Content-Type text/event-stream. The handler is called on the request goroutine,
+// and the events are written concurrently, so panics in the handler or while rendering are handled
+// by the server like in any other handler.
+// Each [Event] is rendered with the request context, see [g.RenderCtx], and sent with one "data:" line for each line
+// of the rendered HTML, and the response is flushed after each event.
+// The response ends when the handler returns. If the client goes away or an event can't be rendered,
+// the request context is cancelled, and events sent after that are dropped.
+//
+// See https://html.spec.whatwg.org/multipage/server-sent-events.html
+func SSE(h SSEHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		r = r.WithContext(ctx)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+		flush(w)
+
+		events := make(chan Event)
+		done := make(chan interface{}, 1)
+		go func() {
+			done <- writeEvents(ctx, cancel, w, events)
+		}()
+		// Wait for the writer to be done, also if the handler panics, and re-panic here if the writer panicked
+		defer func() {
+			close(events)
+			if p := <-done; p != nil {
+				panic(p)
+			}
+		}()
+
+		h(r, events)
+	}
+}
+
+// writeEvents from the channel to w until the channel is closed, flushing after each.
+// If an event can't be written, cancel is called. Events are dropped once the context is done,
+// so the handler never blocks on sending. It returns the value of a panic while rendering, if any.
+func writeEvents(ctx context.Context, cancel context.CancelFunc, w http.ResponseWriter, events <-chan Event) (p interface{}) {
+	defer func() {
+		if p = recover(); p != nil {
+			cancel()
+			for range events {
+			}
+		}
+	}()
+
+	for e := range events {
+		if ctx.Err() != nil {
+			continue
+		}
+		if err := writeEvent(ctx, w, e); err != nil {
+			cancel()
+			continue
+		}
+		flush(w)
+	}
+	return nil
+}
+
+var newlineRemover = strings.NewReplacer("\r\n", "", "\r", "", "\n", "")
+
+// writeEvent e to w in the server-sent events format.
+func writeEvent(ctx context.Context, w io.Writer, e Event) error {
+	var b bytes.Buffer
+	if err := g.RenderCtx(ctx, &b, e.Node); err != nil {
+		return err
+	}
+
+	var event strings.Builder
+	if e.Name != "" {
+		event.WriteString("event: " + newlineRemover.Replace(e.Name) + "\n")
+	}
+	if e.ID != "" {
+		event.WriteString("id: " + newlineRemover.Replace(e.ID) + "\n")
+	}
+	data := strings.ReplaceAll(strings.ReplaceAll(b.String(), "\r\n", "\n"), "\r", "\n")
+	for _, line := range strings.Split(data, "\n") {
+		event.WriteString("data: " + line + "\n")
+	}
+	event.WriteString("\n")
+
+	_, err := io.WriteString(w, event.String())
+	return err
+}
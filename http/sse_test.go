@@ -0,0 +1,153 @@
+package http_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	g "maragu.dev/gomponents"
+	ghttp "maragu.dev/gomponents/http"
+)
+
+func TestSSE(t *testing.T) {
+	t.Run("streams events with names, ids, and multi-line data", func(t *testing.T) {
+		h := ghttp.SSE(func(r *http.Request, events chan<- ghttp.Event) {
+			events <- ghttp.Event{Node: g.El("p", g.Text("Party"))}
+			events <- ghttp.Event{Name: "hat", ID: "2", Node: g.El("pre", g.Text("Party\nhat\r\nyes\rno"))}
+			events <- ghttp.Event{Name: "bad\nname", ID: "\r\n3"}
+		})
+
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if contentType := recorder.Header().Get("Content-Type"); contentType != "text/event-stream" {
+			t.Fatal("content type is", contentType)
+		}
+		if cacheControl := recorder.Header().Get("Cache-Control"); cacheControl != "no-cache" {
+			t.Fatal("cache control is", cacheControl)
+		}
+		expected := "data: <p>Party</p>\n\n" +
+			"event: hat\nid: 2\ndata: <pre>Party\ndata: hat\ndata: yes\ndata: no</pre>\n\n" +
+			"event: badname\nid: 3\ndata: \n\n"
+		if body := recorder.Body.String(); body != expected {
+			t.Fatalf("expected %q but got %q", expected, body)
+		}
+		if !recorder.Flushed {
+			t.Fatal("not flushed")
+		}
+	})
+
+	t.Run("renders events with the request context", func(t *testing.T) {
+		h := ghttp.SSE(func(r *http.Request, events chan<- ghttp.Event) {
+			events <- ghttp.Event{Node: g.El("script")}
+		})
+
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		h.ServeHTTP(recorder, request.WithContext(g.WithCSPNonce(request.Context(), "abc")))
+		if body := recorder.Body.String(); body != "data: <script nonce=\"abc\"></script>\n\n" {
+			t.Fatalf("unexpected body %q", body)
+		}
+	})
+
+	t.Run("stops streaming on render errors", func(t *testing.T) {
+		for _, test := range []struct {
+			name  string
+			event ghttp.Event
+		}{
+			{name: "render error", event: ghttp.Event{Node: erroringNode{}}},
+			{name: "context render error", event: ghttp.Event{Node: g.ContextFunc(func(ctx context.Context, w io.Writer) error {
+				return errors.New("oh no")
+			})}},
+		} {
+			t.Run(test.name, func(t *testing.T) {
+				done := make(chan struct{})
+				h := ghttp.SSE(func(r *http.Request, events chan<- ghttp.Event) {
+					defer close(done)
+					events <- test.event
+					// This send is never received, but doesn't block forever
+					events <- ghttp.Event{Node: g.Text("never")}
+				})
+
+				recorder := httptest.NewRecorder()
+				h.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+				<-done
+				if body := recorder.Body.String(); body != "" {
+					t.Fatalf("unexpected body %q", body)
+				}
+			})
+		}
+	})
+
+	t.Run("stops streaming when the request context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		h := ghttp.SSE(func(r *http.Request, events chan<- ghttp.Event) {
+			events <- ghttp.Event{Node: g.Group{g.Text("party"), g.NodeFunc(func(io.Writer) error {
+				cancel()
+				return nil
+			})}}
+			select {
+			case events <- ghttp.Event{Node: g.Text("never")}:
+			case <-r.Context().Done():
+			}
+		})
+
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+		if body := recorder.Body.String(); body != "data: party\n\n" {
+			t.Fatalf("unexpected body %q", body)
+		}
+	})
+
+	t.Run("panics on the serving goroutine if the handler or rendering panics", func(t *testing.T) {
+		for _, test := range []struct {
+			name string
+			h    ghttp.SSEHandler
+		}{
+			{name: "handler", h: func(r *http.Request, events chan<- ghttp.Event) {
+				panic("no party")
+			}},
+			{name: "rendering", h: func(r *http.Request, events chan<- ghttp.Event) {
+				events <- ghttp.Event{Node: g.NodeFunc(func(io.Writer) error {
+					panic("no party")
+				})}
+				events <- ghttp.Event{Node: g.Text("never")}
+			}},
+		} {
+			t.Run(test.name, func(t *testing.T) {
+				defer func() {
+					if r := recover(); r != "no party" {
+						t.Fatal("unexpected panic", r)
+					}
+				}()
+				ghttp.SSE(test.h).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+			})
+		}
+	})
+}
+
+func ExampleSSE() {
+	h := ghttp.SSE(func(r *http.Request, events chan<- ghttp.Event) {
+		for i := 1; i <= 2; i++ {
+			select {
+			case events <- ghttp.Event{Name: "count", Node: g.El("span", g.Textf("%v", i))}:
+			case <-r.Context().Done():
+				return
+			}
+		}
+	})
+
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	fmt.Print(strings.ReplaceAll(recorder.Body.String(), "\n\n", "\n"))
+	// Output:
+	// event: count
+	// data: <span>1</span>
+	// event: count
+	// data: <span>2</span>
+}
@@ -0,0 +1,156 @@
+package gomponents
+
+import (
+	"io"
+	"strings"
+)
+
+// RenderIndent renders the [Node] n to the writer w like Render, but indented with the given indent string
+// to be readable by humans, for example when debugging or in golden files for tests.
+//
+// Only whitespace that doesn't change how the HTML is displayed is added, so the output is semantically equivalent:
+// Block elements like div and p are put on their own lines if all their children are block elements as well.
+// Elements with text or inline elements like span and a as children are rendered on one line, as are the
+// whitespace-sensitive elements pre, textarea, script, and style.
+// A doctype is put on its own line as well.
+//
+// Nodes that can't be inspected, like [NodeFunc], are rendered as is and treated like inline elements.
+func RenderIndent(w io.Writer, n Node, indent string) error {
+	if n == nil {
+		return nil
+	}
+
+	children := elementChildren([]Node{n})
+	if len(children) == 0 || !allBlock(children) {
+		return n.Render(w)
+	}
+
+	i := indenter{w: w, indent: indent}
+	return i.lines(children, 0)
+}
+
+// indenter renders indented HTML to w.
+type indenter struct {
+	w      io.Writer
+	indent string
+}
+
+// lines renders the block nodes at the given depth, each on its own line.
+func (i indenter) lines(nodes []Node, depth int) error {
+	for j, n := range nodes {
+		if j > 0 {
+			if err := i.newline(depth); err != nil {
+				return err
+			}
+		}
+		if err := i.node(n, depth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// node renders the block node n at the given depth, with its children indented if possible.
+func (i indenter) node(n Node, depth int) error {
+	e, ok := n.(ElementNode)
+	if !ok || isWhitespaceSensitiveElement(e.Name) || isVoidElement(e.Name) {
+		return n.Render(i.w)
+	}
+
+	children := elementChildren(e.Children)
+	if len(children) == 0 || !allBlock(children) {
+		return n.Render(i.w)
+	}
+
+	if err := writeStrings(i.w, "<", e.Name); err != nil {
+		return err
+	}
+	for _, c := range e.Children {
+		if err := renderChild(i.w, c, AttributeType); err != nil {
+			return err
+		}
+	}
+	if _, err := i.w.Write(gt); err != nil {
+		return err
+	}
+
+	if err := i.newline(depth + 1); err != nil {
+		return err
+	}
+	if err := i.lines(children, depth+1); err != nil {
+		return err
+	}
+	if err := i.newline(depth); err != nil {
+		return err
+	}
+
+	return writeStrings(i.w, "</", e.Name, ">")
+}
+
+// newline and indentation for the given depth.
+func (i indenter) newline(depth int) error {
+	return writeStrings(i.w, "\n", strings.Repeat(i.indent, depth))
+}
+
+// writeStrings to w, one at a time.
+func writeStrings(w io.Writer, strs ...string) error {
+	for _, s := range strs {
+		if _, err := io.WriteString(w, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// elementChildren returns the flattened children that are rendered as elements, leaving out attributes.
+func elementChildren(nodes []Node) []Node {
+	var result []Node
+	for _, c := range flatten(nodes) {
+		if d, ok := c.(nodeTypeDescriber); ok && d.Type() == AttributeType {
+			continue
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+// allBlock reports whether all nodes are block nodes, so whitespace between them doesn't change how they're displayed.
+func allBlock(nodes []Node) bool {
+	for _, n := range nodes {
+		switch v := n.(type) {
+		case ElementNode:
+			if !isBlockElement(v.Name) {
+				return false
+			}
+		case RawNode:
+			if !strings.HasPrefix(strings.ToLower(string(v)), "<!doctype") {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// isBlockElement reports whether whitespace around the named element doesn't change how it's displayed.
+func isBlockElement(name string) bool {
+	switch name {
+	case "address", "article", "aside", "base", "blockquote", "body", "caption", "col", "colgroup", "dd", "details",
+		"dialog", "div", "dl", "dt", "fieldset", "figcaption", "figure", "footer", "form", "h1", "h2", "h3", "h4", "h5",
+		"h6", "head", "header", "hgroup", "hr", "html", "legend", "li", "link", "main", "menu", "meta", "nav",
+		"noscript", "ol", "optgroup", "option", "p", "pre", "script", "search", "section", "style", "summary", "table",
+		"tbody", "td", "template", "tfoot", "th", "thead", "title", "tr", "ul":
+		return true
+	}
+	return false
+}
+
+// isWhitespaceSensitiveElement reports whether whitespace in the named element is displayed or otherwise significant.
+func isWhitespaceSensitiveElement(name string) bool {
+	switch name {
+	case "pre", "textarea", "script", "style":
+		return true
+	}
+	return false
+}
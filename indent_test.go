@@ -0,0 +1,142 @@
+package gomponents_test
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	g "maragu.dev/gomponents"
+	"maragu.dev/gomponents/internal/assert"
+)
+
+func TestRenderIndent(t *testing.T) {
+	tests := []struct {
+		name     string
+		node     g.Node
+		expected string
+	}{
+		{
+			name:     "nil node",
+			node:     nil,
+			expected: "",
+		},
+		{
+			name:     "empty block element",
+			node:     g.El("div", g.Attr("class", "hat")),
+			expected: `<div class="hat"></div>`,
+		},
+		{
+			name: "nested block elements",
+			node: g.El("div", g.Attr("class", "hat"),
+				g.El("p", g.Text("Party")),
+				g.Group{g.El("hr"), nil},
+				g.El("ul", g.El("li", g.Text("1")), g.El("li", g.Text("2"))),
+			),
+			expected: `<div class="hat">
+  <p>Party</p>
+  <hr>
+  <ul>
+    <li>1</li>
+    <li>2</li>
+  </ul>
+</div>`,
+		},
+		{
+			name:     "mixed content on one line",
+			node:     g.El("div", g.Text("Party "), g.El("p", g.Text("hat"))),
+			expected: `<div>Party <p>hat</p></div>`,
+		},
+		{
+			name:     "inline elements on one line",
+			node:     g.El("div", g.El("span", g.Text("Party")), g.El("a", g.Text("hat"))),
+			expected: `<div><span>Party</span><a>hat</a></div>`,
+		},
+		{
+			name:     "block elements inside inline elements on one line",
+			node:     g.El("span", g.El("div"), g.El("div")),
+			expected: `<span><div></div><div></div></span>`,
+		},
+		{
+			name: "whitespace-sensitive elements as is",
+			node: g.El("div",
+				g.El("pre", g.El("p", g.Text(" party\n hat "))),
+				g.El("script", g.Raw("if (a) {\n}")),
+			),
+			expected: "<div>\n  <pre><p> party\n hat </p></pre>\n  <script>if (a) {\n}</script>\n</div>",
+		},
+		{
+			name:     "opaque nodes as inline",
+			node:     g.El("div", g.El("p"), outsider{}),
+			expected: `<div><p></p>outsider</div>`,
+		},
+		{
+			name:     "top-level group of block elements",
+			node:     g.Group{g.El("p"), g.El("p")},
+			expected: "<p></p>\n<p></p>",
+		},
+		{
+			name: "document with doctype",
+			node: g.Group{g.Raw("<!doctype html>"), g.El("html", g.Attr("lang", "en"),
+				g.El("head", g.El("meta", g.Attr("charset", "utf-8")), g.El("title", g.Text("Hat"))),
+				g.El("body", g.El("main", g.El("h1", g.Text("Party")))),
+			)},
+			expected: `<!doctype html>
+<html lang="en">
+  <head>
+    <meta charset="utf-8">
+    <title>Hat</title>
+  </head>
+  <body>
+    <main>
+      <h1>Party</h1>
+    </main>
+  </body>
+</html>`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var b strings.Builder
+			if err := g.RenderIndent(&b, test.node, "  "); err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != test.expected {
+				t.Fatalf("expected\n%v\nbut got\n%v", test.expected, b.String())
+			}
+
+			// The output is the same as normal rendering, without the added whitespace
+			if test.node != nil && !strings.Contains(test.name, "whitespace") {
+				lines := strings.Split(b.String(), "\n")
+				for i := range lines {
+					lines[i] = strings.TrimLeft(lines[i], " ")
+				}
+				assert.Equal(t, strings.Join(lines, ""), test.node)
+			}
+		})
+	}
+
+	t.Run("returns render error on cannot write", func(t *testing.T) {
+		n := g.El("div", g.Attr("id", "hat"), g.El("p", g.Text("party")), g.El("ul", g.El("li")))
+		for i := 0; i <= 20; i++ {
+			t.Run(fmt.Sprintf("failing write %v", i), func(t *testing.T) {
+				err := g.RenderIndent(&erroringWriter{failingWrite: i}, n, "\t")
+				assert.Error(t, err)
+			})
+		}
+	})
+}
+
+func ExampleRenderIndent() {
+	n := g.El("ul",
+		g.El("li", g.El("a", g.Attr("href", "/"), g.Text("Home"))),
+		g.El("li", g.El("a", g.Attr("href", "/hats"), g.Text("Hats"))),
+	)
+	_ = g.RenderIndent(os.Stdout, n, "  ")
+	// Output:
+	// <ul>
+	//   <li><a href="/">Home</a></li>
+	//   <li><a href="/hats">Hats</a></li>
+	// </ul>
+}
@@ -0,0 +1,248 @@
+package gomponents
+
+import (
+	"html/template"
+	"io"
+	"strings"
+)
+
+// MinifyOptions for [RenderMinified].
+type MinifyOptions struct {
+	// OmitEndTags omits end tags that the HTML spec says are optional, like the end tags of li and p elements
+	// followed by another li or p element. See https://html.spec.whatwg.org/multipage/syntax.html#optional-tags
+	OmitEndTags bool
+}
+
+// RenderMinified renders the [Node] n to the writer w like Render, but minified to be as small as possible
+// while still displaying the same, for example for clients on slow connections.
+//
+// Text is minified by collapsing runs of whitespace to a single space, and dropping whitespace
+// next to block elements like div and p, except in the whitespace-sensitive elements pre, textarea, script, and style.
+// Attribute values are not quoted if they don't need to be, and empty attribute values and
+// boolean attributes like disabled="disabled" are collapsed to just the attribute name.
+// Optional end tags are omitted if [MinifyOptions.OmitEndTags] is set.
+//
+// Nodes that can't be inspected, like [NodeFunc], and [RawNode] are rendered as is.
+func RenderMinified(w io.Writer, n Node, opts MinifyOptions) error {
+	if n == nil {
+		return nil
+	}
+
+	m := minifier{w: w, opts: opts}
+	return m.nodes(flatten([]Node{n}), "", false)
+}
+
+// minifier renders minified HTML to w.
+type minifier struct {
+	w    io.Writer
+	opts MinifyOptions
+}
+
+// nodes renders the nodes, which are the flattened children of the named parent element, or the root if the name is empty.
+// If preserve is true, text is rendered as is, because it's in a whitespace-sensitive element.
+func (m minifier) nodes(nodes []Node, parent string, preserve bool) error {
+	if !preserve {
+		nodes = minifyText(nodes, parent)
+	}
+
+	for i, n := range nodes {
+		var next Node
+		if i < len(nodes)-1 {
+			next = nodes[i+1]
+		}
+
+		var err error
+		switch n := n.(type) {
+		case ElementNode:
+			err = m.element(n, parent, next, preserve)
+		case AttributeNode:
+			err = m.attribute(n)
+		default:
+			err = n.Render(m.w)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// element e, which has the named parent element and is followed by the next sibling node, which is nil if it's the last one.
+func (m minifier) element(e ElementNode, parent string, next Node, preserve bool) error {
+	if err := writeStrings(m.w, "<", e.Name); err != nil {
+		return err
+	}
+	for _, c := range flatten(e.Children) {
+		if err := m.attributeChild(c); err != nil {
+			return err
+		}
+	}
+	if _, err := m.w.Write(gt); err != nil {
+		return err
+	}
+
+	if isVoidElement(e.Name) {
+		return nil
+	}
+
+	if err := m.nodes(elementChildren(e.Children), e.Name, preserve || isWhitespaceSensitiveElement(e.Name)); err != nil {
+		return err
+	}
+
+	if m.opts.OmitEndTags && canOmitEndTag(e.Name, parent, next) {
+		return nil
+	}
+	return writeStrings(m.w, "</", e.Name, ">")
+}
+
+// attributeChild c of an element, if it's an attribute.
+func (m minifier) attributeChild(c Node) error {
+	if a, ok := c.(AttributeNode); ok {
+		return m.attribute(a)
+	}
+	if d, ok := c.(nodeTypeDescriber); ok && d.Type() == AttributeType {
+		return c.Render(m.w)
+	}
+	return nil
+}
+
+// attribute a with the value unquoted or left out if possible.
+func (m minifier) attribute(a AttributeNode) error {
+	if a.Boolean || a.Value == "" || isBooleanAttribute(a.Name) {
+		return writeStrings(m.w, " ", a.Name)
+	}
+
+	v := template.HTMLEscapeString(a.Value)
+	if strings.ContainsAny(v, " \t\n\f\r=`") {
+		return a.Render(m.w)
+	}
+	return writeStrings(m.w, " ", a.Name, "=", v)
+}
+
+// minifyText in the nodes by collapsing whitespace and dropping it next to block nodes, leaving out empty text.
+// The start and end of the parent count as block nodes if the parent is a block element or the root.
+func minifyText(nodes []Node, parent string) []Node {
+	blockBoundary := parent == "" || isMinifyBlockElement(parent)
+
+	result := make([]Node, 0, len(nodes))
+	for i, n := range nodes {
+		t, ok := n.(TextNode)
+		if !ok {
+			result = append(result, n)
+			continue
+		}
+
+		s := collapseWhitespace(string(t))
+		if (i == 0 && blockBoundary) || (i > 0 && isMinifyBlock(nodes[i-1])) {
+			s = strings.TrimLeft(s, " ")
+		}
+		if (i == len(nodes)-1 && blockBoundary) || (i < len(nodes)-1 && isMinifyBlock(nodes[i+1])) {
+			s = strings.TrimRight(s, " ")
+		}
+		if s != "" {
+			result = append(result, TextNode(s))
+		}
+	}
+	return result
+}
+
+// isMinifyBlock reports whether whitespace next to the node can be dropped, see [isMinifyBlockElement].
+func isMinifyBlock(n Node) bool {
+	if e, ok := n.(ElementNode); ok {
+		return isMinifyBlockElement(e.Name)
+	}
+	return allBlock([]Node{n})
+}
+
+// isMinifyBlockElement reports whether whitespace around the named element can be dropped when minifying.
+// It's like [isBlockElement], but without the elements that aren't displayed, like script and link elements,
+// because the text around those is displayed as if they weren't there, including the whitespace.
+func isMinifyBlockElement(name string) bool {
+	switch name {
+	case "base", "link", "meta", "noscript", "script", "style", "template":
+		return false
+	}
+	return isBlockElement(name)
+}
+
+// collapseWhitespace runs in s to a single space.
+func collapseWhitespace(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range s {
+		switch r {
+		case ' ', '\t', '\n', '\f', '\r':
+			if !space {
+				b.WriteByte(' ')
+			}
+			space = true
+		default:
+			b.WriteRune(r)
+			space = false
+		}
+	}
+	return b.String()
+}
+
+// canOmitEndTag reports whether the end tag of the named element, which has the named parent element and
+// is followed by the next sibling node, can be omitted.
+// The end of the parent is only known if the parent is an element and not the root.
+// See https://html.spec.whatwg.org/multipage/syntax.html#optional-tags
+func canOmitEndTag(name, parent string, next Node) bool {
+	var nextName string
+	if e, ok := next.(ElementNode); ok {
+		nextName = e.Name
+	}
+	last := next == nil && parent != ""
+
+	switch name {
+	case "html", "head", "body", "colgroup":
+		return next == nil || nextName != ""
+	case "li":
+		return last || nextName == "li"
+	case "dt":
+		return nextName == "dt" || nextName == "dd"
+	case "dd":
+		return last || nextName == "dd" || nextName == "dt"
+	case "p":
+		return isParagraphClosingElement(nextName) || (last && !isTransparentParent(parent))
+	case "rt", "rp":
+		return last || nextName == "rt" || nextName == "rp"
+	case "optgroup":
+		return last || nextName == "optgroup" || nextName == "hr"
+	case "option":
+		return last || nextName == "option" || nextName == "optgroup" || nextName == "hr"
+	case "thead":
+		return nextName == "tbody" || nextName == "tfoot"
+	case "tbody":
+		return last || nextName == "tbody" || nextName == "tfoot"
+	case "tfoot":
+		return last
+	case "tr":
+		return last || nextName == "tr"
+	case "td", "th":
+		return last || nextName == "td" || nextName == "th"
+	}
+	return false
+}
+
+// isTransparentParent reports whether the named element is one where a p element end tag at the end can't be omitted.
+func isTransparentParent(name string) bool {
+	switch name {
+	case "a", "audio", "del", "ins", "map", "noscript", "video":
+		return true
+	}
+	return strings.Contains(name, "-")
+}
+
+// isBooleanAttribute reports whether the named attribute is a boolean attribute,
+// where only the presence of the attribute matters and not the value.
+func isBooleanAttribute(name string) bool {
+	switch name {
+	case "allowfullscreen", "async", "autofocus", "autoplay", "checked", "controls", "default", "defer", "disabled",
+		"formnovalidate", "inert", "ismap", "itemscope", "loop", "multiple", "muted", "nomodule", "novalidate", "open",
+		"playsinline", "readonly", "required", "reversed", "selected":
+		return true
+	}
+	return false
+}
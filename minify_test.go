@@ -0,0 +1,220 @@
+package gomponents_test
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	g "maragu.dev/gomponents"
+	"maragu.dev/gomponents/internal/assert"
+)
+
+func TestRenderMinified(t *testing.T) {
+	tests := []struct {
+		name     string
+		node     g.Node
+		expected string
+	}{
+		{
+			name:     "nil node",
+			node:     nil,
+			expected: "",
+		},
+		{
+			name:     "unquoted attribute values",
+			node:     g.El("a", g.Attr("href", "/party?hat=yes&color=red"), g.Attr("class", "party hat"), g.Attr("title", `"Party"`)),
+			expected: `<a href="/party?hat=yes&amp;color=red" class="party hat" title=&#34;Party&#34;></a>`,
+		},
+		{
+			name:     "safe attribute values",
+			node:     g.El("div", g.Attr("id", "hat"), g.Attr("data-x", "a/b.c")),
+			expected: `<div id=hat data-x=a/b.c></div>`,
+		},
+		{
+			name:     "collapsed boolean and empty attributes",
+			node:     g.El("input", g.Attr("disabled", "disabled"), g.Attr("required"), g.Attr("value", ""), g.Attr("hidden", "until-found")),
+			expected: `<input disabled required value hidden=until-found>`,
+		},
+		{
+			name:     "whitespace between block elements",
+			node:     g.El("div", g.Text("\n  "), g.El("p", g.Text("\n    Party  hat\n  ")), g.Text("\n  "), g.El("p"), g.Text("\n")),
+			expected: `<div><p>Party hat</p><p></p></div>`,
+		},
+		{
+			name:     "whitespace next to inline elements",
+			node:     g.El("p", g.Text(" Party \n"), g.El("b", g.Text(" hat ")), g.Text(" !  ")),
+			expected: `<p>Party <b> hat </b> !</p>`,
+		},
+		{
+			name:     "whitespace in inline parents",
+			node:     g.El("span", g.Text("  Party  ")),
+			expected: `<span> Party </span>`,
+		},
+		{
+			name:     "whitespace-sensitive elements as is",
+			node:     g.El("div", g.El("pre", g.El("b", g.Text("  Party\n  hat  "))), g.El("textarea", g.Text(" hat ")), g.El("script", g.Raw("if (a) {\n}"))),
+			expected: "<div><pre><b>  Party\n  hat  </b></pre><textarea> hat </textarea><script>if (a) {\n}</script></div>",
+		},
+		{
+			name:     "whitespace next to elements that aren't displayed",
+			node:     g.El("div", g.Text("Party "), g.El("script", g.Raw("party()")), g.Text(" hat")),
+			expected: `<div>Party <script>party()</script> hat</div>`,
+		},
+		{
+			name:     "whitespace next to void elements that aren't displayed",
+			node:     g.El("p", g.Text("Party "), g.El("link", g.Attr("rel", "stylesheet")), g.Text(" hat "), g.El("meta"), g.Text(" !")),
+			expected: `<p>Party <link rel=stylesheet> hat <meta> !</p>`,
+		},
+		{
+			name:     "whitespace in template elements",
+			node:     g.El("template", g.Text(" Party "), g.El("style"), g.Text(" hat ")),
+			expected: `<template> Party <style></style> hat </template>`,
+		},
+		{
+			name:     "raw and opaque nodes as is",
+			node:     g.El("div", g.Raw(" <b> x </b> "), outsider{}, g.Group{nil, g.Attr("id", "a"), customAttr{}}),
+			expected: `<div id=a data-custom="yes"> <b> x </b> outsider</div>`,
+		},
+		{
+			name:     "top-level attribute",
+			node:     g.Attr("class", "hat"),
+			expected: ` class=hat`,
+		},
+		{
+			name: "document",
+			node: g.Group{g.Raw("<!doctype html>"), g.Text("\n"), g.El("html", g.Attr("lang", "en"),
+				g.El("head", g.El("title", g.Text(" Hat "))),
+				g.Text("\n"),
+				g.El("body", g.El("ul", g.El("li", g.Text("Party")), g.El("li", g.Text("Hat")))),
+			)},
+			expected: `<!doctype html><html lang=en><head><title>Hat</title></head><body><ul><li>Party</li><li>Hat</li></ul></body></html>`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var b strings.Builder
+			if err := g.RenderMinified(&b, test.node, g.MinifyOptions{}); err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != test.expected {
+				t.Fatalf("expected\n%v\nbut got\n%v", test.expected, b.String())
+			}
+		})
+	}
+
+	t.Run("returns render error on cannot write", func(t *testing.T) {
+		n := g.El("div", g.Attr("id", "hat"), g.Attr("class", "party hat"), g.Attr("required"), customAttr{},
+			g.El("p", g.Text("party")), g.Raw("raw"), g.El("br"))
+		for i := 0; i <= 20; i++ {
+			t.Run(fmt.Sprintf("failing write %v", i), func(t *testing.T) {
+				err := g.RenderMinified(&erroringWriter{failingWrite: i}, n, g.MinifyOptions{OmitEndTags: true})
+				assert.Error(t, err)
+			})
+		}
+	})
+}
+
+func TestRenderMinifiedOmitEndTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		node     g.Node
+		expected string
+	}{
+		{
+			name:     "document",
+			node:     g.El("html", g.El("head", g.El("title")), g.El("body", g.El("p"))),
+			expected: `<html><head><title></title><body><p>`,
+		},
+		{
+			name:     "comment after body",
+			node:     g.El("html", g.El("body"), g.Raw("<!-- party -->")),
+			expected: `<html><body></body><!-- party -->`,
+		},
+		{
+			name:     "lists",
+			node:     g.Group{g.El("ul", g.El("li"), g.El("li")), g.El("dl", g.El("dt"), g.El("dd"), g.El("dt"), g.El("dd"))},
+			expected: `<ul><li><li></ul><dl><dt><dd><dt><dd></dl>`,
+		},
+		{
+			name:     "li followed by text",
+			node:     g.El("ul", g.El("li"), g.Text("party")),
+			expected: `<ul><li></li>party</ul>`,
+		},
+		{
+			name:     "dt at the end",
+			node:     g.El("dl", g.El("dt")),
+			expected: `<dl><dt></dt></dl>`,
+		},
+		{
+			name:     "paragraphs",
+			node:     g.El("div", g.El("p"), g.El("p"), g.El("span"), g.El("p"), g.El("section"), g.El("p")),
+			expected: `<div><p><p></p><span></span><p><section></section><p></div>`,
+		},
+		{
+			name:     "paragraph at the end of a transparent parent",
+			node:     g.Group{g.El("a", g.El("p")), g.El("my-element", g.El("p"))},
+			expected: `<a><p></p></a><my-element><p></p></my-element>`,
+		},
+		{
+			name:     "elements at the end of the root",
+			node:     g.Group{g.El("li"), g.El("p")},
+			expected: `<li></li><p></p>`,
+		},
+		{
+			name: "tables",
+			node: g.El("table",
+				g.El("colgroup", g.El("col")),
+				g.El("thead", g.El("tr", g.El("th"), g.El("th"))),
+				g.El("tbody", g.El("tr", g.El("td")), g.El("tr", g.El("td"))),
+				g.El("tfoot", g.El("tr", g.El("td"))),
+			),
+			expected: `<table><colgroup><col><thead><tr><th><th><tbody><tr><td><tr><td><tfoot><tr><td></table>`,
+		},
+		{
+			name:     "selects",
+			node:     g.El("select", g.El("option"), g.El("optgroup", g.El("option"), g.El("option")), g.El("optgroup"), g.El("hr"), g.El("option")),
+			expected: `<select><option><optgroup><option><option><optgroup><hr><option></select>`,
+		},
+		{
+			name:     "ruby",
+			node:     g.El("ruby", g.Text("漢"), g.El("rp"), g.El("rt"), g.El("rp")),
+			expected: `<ruby>漢<rp><rt><rp></ruby>`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var b strings.Builder
+			if err := g.RenderMinified(&b, test.node, g.MinifyOptions{OmitEndTags: true}); err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != test.expected {
+				t.Fatalf("expected\n%v\nbut got\n%v", test.expected, b.String())
+			}
+		})
+	}
+}
+
+// customAttr is an attribute [g.Node] that can't be inspected.
+type customAttr struct{}
+
+func (customAttr) Render(w io.Writer) error {
+	_, err := io.WriteString(w, ` data-custom="yes"`)
+	return err
+}
+
+func (customAttr) Type() g.NodeType {
+	return g.AttributeType
+}
+
+func ExampleRenderMinified() {
+	n := g.El("ul", g.Attr("class", "hats"),
+		g.El("li", g.Text("\n  Party hat\n")),
+		g.El("li", g.Text("Top hat")),
+	)
+	_ = g.RenderMinified(os.Stdout, n, g.MinifyOptions{OmitEndTags: true})
+	// Output: <ul class=hats><li>Party hat<li>Top hat</ul>
+}
@@ -0,0 +1,452 @@
+// Package parse provides a parser that turns HTML 5 into a tree of [g.Node]-s.
+//
+// The tree is built from [g.El], [g.Attr], [g.Text], and [g.Raw], so parsed HTML can be inspected,
+// transformed, and embedded like any other gomponents nodes.
+package parse
+
+import (
+	"html"
+	"io"
+	"strings"
+
+	g "maragu.dev/gomponents"
+)
+
+// HTML reads HTML 5 from r and parses it into a [g.Group] of the top-level nodes.
+//
+// Parsing is lenient like in browsers, so only errors from reading r are returned.
+// Unlike browsers, no html, head, or body elements are inserted, so HTML fragments stay fragments.
+//
+// Element and attribute names are lower-cased like in browsers, except in svg and math elements, where names like
+// viewBox are case-sensitive, so they're kept as written there. Void elements never have children, and end tags that don't
+// match an open element are ignored. Some end tags that HTML allows to omit are implied, like for li and p elements.
+//
+// Character references in text and attribute values are decoded, and the text is returned as [g.Text].
+// Contents of raw text elements like script and style are kept as [g.Raw].
+// Comments, the doctype, and CDATA sections are kept as [g.Raw] as well.
+//
+// Rendering a parsed [g.Node] gives the same HTML that was parsed, if that HTML was rendered by gomponents
+// from elements, attributes, and text. Other HTML may be normalized, for example character references and names.
+func HTML(r io.Reader) (g.Group, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{s: string(b)}
+	p.parse()
+	return p.root.children, nil
+}
+
+// element is an open element while parsing.
+type element struct {
+	name     string
+	children []g.Node
+}
+
+// parser for HTML 5 in s.
+type parser struct {
+	s    string
+	pos  int
+	root element
+	open []*element
+}
+
+// parse the whole input.
+func (p *parser) parse() {
+	for p.pos < len(p.s) {
+		i := strings.IndexByte(p.s[p.pos:], '<')
+		if i < 0 {
+			p.text(p.s[p.pos:])
+			p.pos = len(p.s)
+			break
+		}
+		if i > 0 {
+			p.text(p.s[p.pos : p.pos+i])
+			p.pos += i
+		}
+
+		if !p.markup() {
+			// Not markup, so the "<" is just text
+			p.text("<")
+			p.pos++
+		}
+	}
+
+	for len(p.open) > 0 {
+		p.pop()
+	}
+}
+
+// markup at the current position, which starts with "<". Reports whether it was markup.
+func (p *parser) markup() bool {
+	rest := p.s[p.pos+1:]
+	switch {
+	case strings.HasPrefix(rest, "!--"):
+		end := strings.Index(p.s[p.pos+4:], "-->")
+		if end < 0 {
+			p.raw(p.s[p.pos:])
+			p.pos = len(p.s)
+			return true
+		}
+		p.raw(p.s[p.pos : p.pos+4+end+3])
+		p.pos += 4 + end + 3
+		return true
+
+	case strings.HasPrefix(rest, "![CDATA["):
+		end := strings.Index(p.s[p.pos:], "]]>")
+		if end < 0 {
+			p.raw(p.s[p.pos:])
+			p.pos = len(p.s)
+			return true
+		}
+		p.raw(p.s[p.pos : p.pos+end+3])
+		p.pos += end + 3
+		return true
+
+	case strings.HasPrefix(rest, "!"), strings.HasPrefix(rest, "?"):
+		// Doctype and bogus comments
+		end := strings.IndexByte(p.s[p.pos:], '>')
+		if end < 0 {
+			p.raw(p.s[p.pos:])
+			p.pos = len(p.s)
+			return true
+		}
+		p.raw(p.s[p.pos : p.pos+end+1])
+		p.pos += end + 1
+		return true
+
+	case strings.HasPrefix(rest, "/"):
+		if len(rest) < 2 || !isASCIILetter(rest[1]) {
+			return false
+		}
+		p.pos += 2
+		name := p.name()
+		// Anything after the name in an end tag is ignored
+		if end := strings.IndexByte(p.s[p.pos:], '>'); end < 0 {
+			p.pos = len(p.s)
+		} else {
+			p.pos += end + 1
+		}
+		p.endTag(name)
+		return true
+
+	case len(rest) > 0 && isASCIILetter(rest[0]):
+		p.pos++
+		p.startTag()
+		return true
+
+	default:
+		return false
+	}
+}
+
+// startTag at the current position, which is right after the "<".
+func (p *parser) startTag() {
+	name := p.name()
+	lower := toLowerASCII(name)
+	foreign := p.inForeignContent() || lower == "svg" || lower == "math"
+	if !foreign {
+		name = lower
+	}
+
+	var attrs []g.Node
+	selfClosing := false
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.s) {
+			break
+		}
+		if p.s[p.pos] == '>' {
+			p.pos++
+			break
+		}
+		if strings.HasPrefix(p.s[p.pos:], "/>") {
+			selfClosing = true
+			p.pos += 2
+			break
+		}
+		if p.s[p.pos] == '/' {
+			p.pos++
+			continue
+		}
+		attrs = append(attrs, p.attr(!foreign))
+	}
+
+	p.implyEndTags(name)
+
+	if isVoidElement(lower) || (selfClosing && p.inForeignContent()) {
+		p.append(g.El(name, attrs...))
+		return
+	}
+
+	p.open = append(p.open, &element{name: name, children: attrs})
+
+	switch lower {
+	case "script", "style", "xmp", "iframe", "noembed", "noframes":
+		p.rawText(lower, false)
+	case "textarea", "title":
+		if !p.inForeignContent() {
+			p.rawText(lower, true)
+		}
+	}
+}
+
+// attr at the current position, with the name lower-cased if lower is true.
+func (p *parser) attr(lower bool) g.Node {
+	start := p.pos
+	// The first character is always part of the name, even if it's "="
+	p.pos++
+	for p.pos < len(p.s) && !isSpace(p.s[p.pos]) && !strings.ContainsRune("/>=", rune(p.s[p.pos])) {
+		p.pos++
+	}
+	name := p.s[start:p.pos]
+	if lower {
+		name = toLowerASCII(name)
+	}
+
+	p.skipSpace()
+	if p.pos >= len(p.s) || p.s[p.pos] != '=' {
+		return g.Attr(name)
+	}
+	p.pos++
+	p.skipSpace()
+
+	if p.pos >= len(p.s) {
+		return g.Attr(name, "")
+	}
+
+	var value string
+	switch q := p.s[p.pos]; q {
+	case '"', '\'':
+		end := strings.IndexByte(p.s[p.pos+1:], q)
+		if end < 0 {
+			value = p.s[p.pos+1:]
+			p.pos = len(p.s)
+		} else {
+			value = p.s[p.pos+1 : p.pos+1+end]
+			p.pos += end + 2
+		}
+	default:
+		start := p.pos
+		for p.pos < len(p.s) && !isSpace(p.s[p.pos]) && p.s[p.pos] != '>' {
+			p.pos++
+		}
+		value = p.s[start:p.pos]
+	}
+
+	return g.Attr(name, html.UnescapeString(value))
+}
+
+// rawText content of the current open element, up to and including its end tag.
+// If escapable is true, character references are decoded and the content is text, otherwise it's raw.
+func (p *parser) rawText(name string, escapable bool) {
+	end := len(p.s)
+	next := len(p.s)
+	lower := toLowerASCII(p.s[p.pos:])
+	for i := 0; ; {
+		j := strings.Index(lower[i:], "</"+name)
+		if j < 0 {
+			break
+		}
+		j += i
+		after := j + 2 + len(name)
+		if after == len(lower) || isSpace(lower[after]) || lower[after] == '/' || lower[after] == '>' {
+			end = p.pos + j
+			if k := strings.IndexByte(lower[after:], '>'); k < 0 {
+				next = len(p.s)
+			} else {
+				next = p.pos + after + k + 1
+			}
+			break
+		}
+		i = after
+	}
+
+	if content := p.s[p.pos:end]; content != "" {
+		if escapable {
+			p.append(g.Text(html.UnescapeString(content)))
+		} else {
+			p.append(g.Raw(content))
+		}
+	}
+	p.pos = next
+	p.pop()
+}
+
+// endTag closes the innermost open element with the given name, and all elements opened after it.
+// If no open element has the name, the end tag is ignored.
+func (p *parser) endTag(name string) {
+	for i := len(p.open) - 1; i >= 0; i-- {
+		if strings.EqualFold(p.open[i].name, name) {
+			for len(p.open) > i {
+				p.pop()
+			}
+			return
+		}
+	}
+}
+
+// closesP are the start tags that imply the end tag of an open p element.
+var closesP = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "details": true, "dialog": true, "div": true,
+	"dl": true, "fieldset": true, "figcaption": true, "figure": true, "footer": true, "form": true, "h1": true, "h2": true,
+	"h3": true, "h4": true, "h5": true, "h6": true, "header": true, "hgroup": true, "hr": true, "main": true, "menu": true,
+	"nav": true, "ol": true, "p": true, "pre": true, "search": true, "section": true, "table": true, "ul": true,
+}
+
+// impliedEndTag describes which open elements a start tag closes, unless one of the boundary elements is in between.
+// If scoped is true, the scope boundaries are boundaries as well.
+type impliedEndTag struct {
+	closes     []string
+	boundaries []string
+	scoped     bool
+}
+
+// impliedEndTags for start tags, see https://html.spec.whatwg.org/multipage/syntax.html#optional-tags
+var impliedEndTags = map[string]impliedEndTag{
+	"li":       {closes: []string{"li"}, boundaries: []string{"ul", "ol", "menu"}, scoped: true},
+	"dt":       {closes: []string{"dt", "dd"}, boundaries: []string{"dl"}, scoped: true},
+	"dd":       {closes: []string{"dt", "dd"}, boundaries: []string{"dl"}, scoped: true},
+	"option":   {closes: []string{"option"}, boundaries: []string{"select", "datalist", "optgroup"}},
+	"optgroup": {closes: []string{"option", "optgroup"}, boundaries: []string{"select"}},
+	"tr":       {closes: []string{"tr"}, boundaries: []string{"table", "thead", "tbody", "tfoot"}},
+	"td":       {closes: []string{"td", "th"}, boundaries: []string{"tr", "table"}},
+	"th":       {closes: []string{"td", "th"}, boundaries: []string{"tr", "table"}},
+	"thead":    {closes: []string{"thead", "tbody", "tfoot"}, boundaries: []string{"table"}},
+	"tbody":    {closes: []string{"thead", "tbody", "tfoot"}, boundaries: []string{"table"}},
+	"tfoot":    {closes: []string{"thead", "tbody", "tfoot"}, boundaries: []string{"table"}},
+}
+
+// scopeBoundaries are elements that scoped end tags are never implied through.
+var scopeBoundaries = []string{"html", "table", "td", "th", "caption", "template", "object", "button", "svg", "math"}
+
+// implyEndTags closes open elements whose end tags are implied by a start tag with the given name.
+func (p *parser) implyEndTags(name string) {
+	lower := strings.ToLower(name)
+
+	t, ok := impliedEndTags[lower]
+	if !ok && closesP[lower] {
+		t, ok = impliedEndTag{closes: []string{"p"}, scoped: true}, true
+	}
+	if !ok {
+		return
+	}
+
+	for i := len(p.open) - 1; i >= 0; i-- {
+		openName := strings.ToLower(p.open[i].name)
+		if contains(t.closes, openName) {
+			for len(p.open) > i {
+				p.pop()
+			}
+			return
+		}
+		if contains(t.boundaries, openName) || (t.scoped && contains(scopeBoundaries, openName)) {
+			return
+		}
+	}
+}
+
+// inForeignContent reports whether an svg or math element is open.
+func (p *parser) inForeignContent() bool {
+	for _, e := range p.open {
+		switch strings.ToLower(e.name) {
+		case "svg", "math":
+			return true
+		}
+	}
+	return false
+}
+
+// name of a tag at the current position.
+func (p *parser) name() string {
+	start := p.pos
+	for p.pos < len(p.s) && !isSpace(p.s[p.pos]) && p.s[p.pos] != '/' && p.s[p.pos] != '>' {
+		p.pos++
+	}
+	return p.s[start:p.pos]
+}
+
+// skipSpace at the current position.
+func (p *parser) skipSpace() {
+	for p.pos < len(p.s) && isSpace(p.s[p.pos]) {
+		p.pos++
+	}
+}
+
+// text appends decoded text to the current element.
+func (p *parser) text(s string) {
+	p.append(g.Text(html.UnescapeString(s)))
+}
+
+// raw appends s unchanged to the current element.
+func (p *parser) raw(s string) {
+	p.append(g.Raw(s))
+}
+
+// append n to the children of the current element.
+func (p *parser) append(n g.Node) {
+	current := &p.root
+	if len(p.open) > 0 {
+		current = p.open[len(p.open)-1]
+	}
+
+	// Merge adjacent text, which happens when a "<" isn't markup
+	if t, ok := n.(g.TextNode); ok && len(current.children) > 0 {
+		if prev, ok := current.children[len(current.children)-1].(g.TextNode); ok {
+			current.children[len(current.children)-1] = prev + t
+			return
+		}
+	}
+
+	current.children = append(current.children, n)
+}
+
+// pop the current element off the open elements, and append it to its parent.
+func (p *parser) pop() {
+	e := p.open[len(p.open)-1]
+	p.open = p.open[:len(p.open)-1]
+	p.append(g.El(e.name, e.children...))
+}
+
+// isVoidElement reports whether the named element is a void element that doesn't have an end tag.
+// It must match the void elements that [g.El] doesn't render end tags for.
+func isVoidElement(name string) bool {
+	switch name {
+	case "area", "base", "br", "col", "command", "embed", "hr", "img", "input", "keygen", "link", "meta", "param", "source", "track", "wbr":
+		return true
+	}
+	return false
+}
+
+// toLowerASCII returns s with ASCII letters in lower case, keeping the byte positions of s, unlike [strings.ToLower].
+func toLowerASCII(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if 'A' <= c && c <= 'Z' {
+			b[i] = c + 'a' - 'A'
+		}
+	}
+	return string(b)
+}
+
+func isASCIILetter(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func isSpace(c byte) bool {
+	switch c {
+	case ' ', '\t', '\n', '\r', '\f':
+		return true
+	}
+	return false
+}
+
+func contains(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
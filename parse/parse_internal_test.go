@@ -0,0 +1,36 @@
+package parse
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	g "maragu.dev/gomponents"
+)
+
+func TestIsVoidElement(t *testing.T) {
+	t.Run("matches the void elements that g.El doesn't render end tags for", func(t *testing.T) {
+		b, err := os.ReadFile("../html/elements.go")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// All elements with helpers in the html package, and obsolete elements that are void in some specs
+		names := []string{"basefont", "bgsound", "command", "frame", "image", "isindex", "keygen", "menuitem", "param"}
+		for _, m := range regexp.MustCompile(`g\.El\("([^"]+)"`).FindAllStringSubmatch(string(b), -1) {
+			names = append(names, m[1])
+		}
+
+		for _, name := range names {
+			var rendered strings.Builder
+			if err := g.El(name, g.Text("x")).Render(&rendered); err != nil {
+				t.Fatal(err)
+			}
+			rendersEndTag := strings.HasSuffix(rendered.String(), "</"+name+">")
+			if isVoidElement(name) == rendersEndTag {
+				t.Errorf("%v: void element in parse is %v, and g.El renders an end tag is %v", name, isVoidElement(name), rendersEndTag)
+			}
+		}
+	})
+}
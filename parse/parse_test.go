@@ -0,0 +1,168 @@
+package parse_test
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	g "maragu.dev/gomponents"
+	c "maragu.dev/gomponents/components"
+	. "maragu.dev/gomponents/html"
+	"maragu.dev/gomponents/internal/assert"
+	"maragu.dev/gomponents/parse"
+)
+
+func TestHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"empty", ``, ``},
+		{"text only", `party hat`, `party hat`},
+		{"element with attributes and children", `<div class="hat" id="party"><span>Hi</span>!</div>`, `<div class="hat" id="party"><span>Hi</span>!</div>`},
+		{"boolean and empty attributes", `<input required value="">`, `<input required value="">`},
+		{"single-quoted and unquoted attributes", `<a href='/hat' target=_blank>Hat</a>`, `<a href="/hat" target="_blank">Hat</a>`},
+		{"void elements without end tags", `<p>a<br>b<img src="hat.jpg"></p>`, `<p>a<br>b<img src="hat.jpg"></p>`},
+		{"void elements with stray end tags", `<br></br><hr/>`, `<br><hr>`},
+		{"self-closing non-void elements", `<div/>hat</div>`, `<div>hat</div>`},
+		{"entities in text and attributes", `<p title="a &amp; b">&lt;hat&gt; &amp; &#34;party&#34; &nbsp;</p>`, `<p title="a &amp; b">&lt;hat&gt; &amp; &#34;party&#34; ` + " " + `</p>`},
+		{"raw text elements", `<script>if (a < b && c > d) { x = "</p>" }</script>`, `<script>if (a < b && c > d) { x = "</p>" }</script>`},
+		{"style elements", `<style>a > b { color: red }</style>`, `<style>a > b { color: red }</style>`},
+		{"end tags of raw text elements are case-insensitive", `<SCRIPT>1 < 2</Script >hat`, `<script>1 < 2</script>hat`},
+		{"escapable raw text elements", `<textarea><b>hat</b> &amp;</textarea>`, `<textarea>&lt;b&gt;hat&lt;/b&gt; &amp;</textarea>`},
+		{"comments, doctype and CDATA as raw", `<!doctype html><!-- hat --><svg><![CDATA[x]]></svg>`, `<!doctype html><!-- hat --><svg><![CDATA[x]]></svg>`},
+		{"less-than that is not markup", `a < b <3 </ c`, `a &lt; b &lt;3 &lt;/ c`},
+		{"unclosed elements", `<div><span>hat`, `<div><span>hat</span></div>`},
+		{"unmatched end tags", `<div>hat</span></div></p>`, `<div>hat</div>`},
+		{"end tags closing inner elements", `<div><span><b>hat</div>party`, `<div><span><b>hat</b></span></div>party`},
+		{"implied li end tags", `<ul><li>a<li>b<ul><li>c</ul></ul>`, `<ul><li>a</li><li>b<ul><li>c</li></ul></li></ul>`},
+		{"implied p end tags", `<p>a<div>b</div><p>c<p>d`, `<p>a</p><div>b</div><p>c</p><p>d</p>`},
+		{"implied table end tags", `<table><tr><td>a<td>b<tr><th>c</table>`, `<table><tr><td>a</td><td>b</td></tr><tr><th>c</th></tr></table>`},
+		{"implied option end tags", `<select><option>a<option>b</select>`, `<select><option>a</option><option>b</option></select>`},
+		{"names are lower-cased", `<P CLASS="hat">a<BR>b</P>`, `<p class="hat">a<br>b</p>`},
+		{"case is kept in names in svg", `<svg viewBox="0 0 1 1"><linearGradient/></svg>`, `<svg viewBox="0 0 1 1"><linearGradient></linearGradient></svg>`},
+		{"self-closing in foreign content", `<svg><path d="M0"/><circle/></svg>`, `<svg><path d="M0"></path><circle></circle></svg>`},
+		{"whitespace in tags", "<div\n class = \"hat\"\tid=party >x</div >", `<div class="hat" id="party">x</div>`},
+		{"unterminated tag", `<div class="hat`, `<div class="hat"></div>`},
+		{"unterminated comment", `<!-- hat`, `<!-- hat`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			n, err := parse.HTML(strings.NewReader(test.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, test.expected, n)
+		})
+	}
+
+	t.Run("returns nodes from gomponents", func(t *testing.T) {
+		n, err := parse.HTML(strings.NewReader(`<a href="/">&lt;Hat&gt;</a>`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(n) != 1 {
+			t.Fatal("unexpected length", len(n))
+		}
+		e, ok := n[0].(g.ElementNode)
+		if !ok || e.Name != "a" || len(e.Children) != 2 {
+			t.Fatal("unexpected element", n[0])
+		}
+		if a, ok := e.Children[0].(g.AttributeNode); !ok || a.Name != "href" || a.Value != "/" {
+			t.Fatal("unexpected attribute", e.Children[0])
+		}
+		if text, ok := e.Children[1].(g.TextNode); !ok || text != "<Hat>" {
+			t.Fatal("unexpected text", e.Children[1])
+		}
+	})
+
+	t.Run("returns read errors", func(t *testing.T) {
+		_, err := parse.HTML(erroringReader{})
+		assert.Error(t, err)
+	})
+
+	t.Run("round-trips a full page", func(t *testing.T) {
+		page := c.HTML5(c.HTML5Props{
+			Title:       "Party <hats>",
+			Description: `"Best" hats & more`,
+			Language:    "en",
+			Head:        []g.Node{Link(Rel("stylesheet"), Href("/hat.css?a=1&b=2")), Script(g.Raw(`if (a<b) {}`))},
+			Body: []g.Node{
+				Nav(Ul(Li(A(Href("/"), g.Text("Home"))), Li(A(Href("/about"), g.Text("About"))))),
+				Form(Action("/hats"), Method("post"),
+					Label(For("name"), g.Text("Name")), Input(ID("name"), Name("name"), Required()),
+					Select(Option(Value("1"), Selected(), g.Text("One")), Option(Value("2"), g.Text("Two"))),
+					Textarea(g.Text("</textarea> & <b>")),
+				),
+				Table(THead(Tr(Th(g.Text("A")))), TBody(Tr(Td(g.Text("1"))))),
+				P(g.Text("Hat "), Em(g.Text("party")), Br(), g.Text(`'quoted'`)),
+				Pre(g.Text("\n  indented\n")),
+			},
+		})
+
+		expected := page.(g.Group).String()
+		n, err := parse.HTML(strings.NewReader(expected))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, expected, n)
+	})
+}
+
+func ExampleHTML() {
+	n, err := parse.HTML(strings.NewReader(`<p class="hat">Party &amp; hats<br></p>`))
+	if err != nil {
+		panic(err)
+	}
+	_ = Div(n).Render(os.Stdout)
+	// Output: <div><p class="hat">Party &amp; hats<br></p></div>
+}
+
+type erroringReader struct{}
+
+func (erroringReader) Read([]byte) (int, error) {
+	return 0, errors.New("don't want to")
+}
+
+func FuzzHTML(f *testing.F) {
+	f.Add("div", "class", "party hat", "Hello, <world> & friends")
+	f.Add("textarea", "data-x", `"quotes"`, "</textarea>")
+	f.Add("p", "title", "a&b", "'apostrophes'")
+	f.Add("span", "hidden", "", "")
+
+	f.Fuzz(func(t *testing.T, name, attrName, attrValue, text string) {
+		// Only fuzz values that gomponents escapes, since names must be trusted
+		switch name {
+		case "div", "textarea", "p", "span", "title", "li", "option":
+		default:
+			t.Skip()
+		}
+		switch attrName {
+		case "class", "data-x", "title", "hidden":
+		default:
+			t.Skip()
+		}
+
+		n := g.El(name, g.Attr(attrName, attrValue), g.Text(text), g.El("span", g.Text(text)))
+		if name == "textarea" || name == "title" {
+			// Escapable raw text elements can't have element children
+			n = g.El(name, g.Attr(attrName, attrValue), g.Text(text))
+		}
+		expected := n.(g.ElementNode).String()
+
+		parsed, err := parse.HTML(strings.NewReader(expected))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var b strings.Builder
+		if err := parsed.Render(&b); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != expected {
+			t.Fatalf("expected %q but got %q", expected, b.String())
+		}
+	})
+}
@@ -0,0 +1,155 @@
+package gomponents
+
+// TransformRule for [Transform]. If Match reports true for a [Node], the [Node] is replaced by the result of Rewrite.
+// If Rewrite returns nil, the [Node] is removed from the tree.
+type TransformRule struct {
+	Match   NodeMatcher
+	Rewrite func(Node) Node
+}
+
+// NodeMatcher reports whether a [Node] should be rewritten by a [TransformRule].
+type NodeMatcher func(Node) bool
+
+// Transform the [Node] tree rooted at n with the given rules, and return the new tree.
+// The tree is traversed bottom-up: the children of a [Node] are transformed first, and then all rules are tried
+// in order on the [Node] with the transformed children, each seeing the result of the previous.
+// Nodes returned by a Rewrite are not transformed again, so rules can wrap nodes in new nodes that they match.
+// The given tree is never modified, so it's safe to transform shared trees.
+// Only [ElementNode] and [Group] have children, see [Walk].
+func Transform(n Node, rules ...TransformRule) Node {
+	if n == nil {
+		return nil
+	}
+
+	switch v := n.(type) {
+	case ElementNode:
+		n = ElementNode{Name: v.Name, Children: transformChildren(v.Children, rules)}
+	case Group:
+		n = Group(transformChildren(v, rules))
+	}
+
+	for _, r := range rules {
+		if !r.Match(n) {
+			continue
+		}
+		n = r.Rewrite(n)
+		if n == nil {
+			return nil
+		}
+	}
+	return n
+}
+
+// transformChildren with the given rules into a new slice, leaving out removed children.
+func transformChildren(children []Node, rules []TransformRule) []Node {
+	if children == nil {
+		return nil
+	}
+
+	result := make([]Node, 0, len(children))
+	for _, c := range children {
+		if c = Transform(c, rules...); c != nil {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// MatchElement matches an [ElementNode] with the given name.
+func MatchElement(name string) NodeMatcher {
+	return func(n Node) bool {
+		e, ok := n.(ElementNode)
+		return ok && e.Name == name
+	}
+}
+
+// MatchHasAttr matches an [ElementNode] that has an [AttributeNode] with the given name, see [ElementNode.Attribute].
+func MatchHasAttr(name string) NodeMatcher {
+	return func(n Node) bool {
+		e, ok := n.(ElementNode)
+		if !ok {
+			return false
+		}
+		_, ok = e.Attribute(name)
+		return ok
+	}
+}
+
+// MatchType matches any [Node] of the given [NodeType].
+// Nodes that don't describe their type are [ElementType], like when rendering.
+func MatchType(t NodeType) NodeMatcher {
+	return func(n Node) bool {
+		if d, ok := n.(nodeTypeDescriber); ok {
+			return d.Type() == t
+		}
+		return t == ElementType
+	}
+}
+
+// MatchAll matches a [Node] if all the given matchers match it.
+func MatchAll(matchers ...NodeMatcher) NodeMatcher {
+	return func(n Node) bool {
+		for _, m := range matchers {
+			if !m(n) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// RemoveNode is a rewrite function for [TransformRule] that removes the [Node] from the tree.
+func RemoveNode(Node) Node {
+	return nil
+}
+
+// SetNodeAttr returns a rewrite function for [TransformRule] that sets the attribute with the given name
+// and optional value on an [ElementNode], like [Attr] does.
+// Direct attributes of the element with the same name are removed, and the new attribute is appended.
+// Nodes that aren't an [ElementNode] are returned unchanged.
+func SetNodeAttr(name string, value ...string) func(Node) Node {
+	a := Attr(name, value...)
+	return func(n Node) Node {
+		e, ok := n.(ElementNode)
+		if !ok {
+			return n
+		}
+
+		children := make([]Node, 0, len(e.Children)+1)
+		for _, c := range flatten(e.Children) {
+			if ca, ok := c.(AttributeNode); ok && ca.Name == name {
+				continue
+			}
+			children = append(children, c)
+		}
+		return ElementNode{Name: e.Name, Children: append(children, a)}
+	}
+}
+
+// Attribute returns the first [AttributeNode] with the given name among the children of the element,
+// including children of direct [Group] children, since those are rendered as attributes of the element.
+// Attributes not created with [Attr], like custom [Node] implementations, are not found.
+func (e ElementNode) Attribute(name string) (AttributeNode, bool) {
+	for _, c := range flatten(e.Children) {
+		if a, ok := c.(AttributeNode); ok && a.Name == name {
+			return a, true
+		}
+	}
+	return AttributeNode{}, false
+}
+
+// flatten nested [Group] nodes in the given nodes into one slice, leaving out nil nodes.
+func flatten(nodes []Node) []Node {
+	var result []Node
+	for _, n := range nodes {
+		switch v := n.(type) {
+		case nil:
+			continue
+		case Group:
+			result = append(result, flatten(v)...)
+		default:
+			result = append(result, n)
+		}
+	}
+	return result
+}
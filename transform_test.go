@@ -0,0 +1,163 @@
+package gomponents_test
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	g "maragu.dev/gomponents"
+	"maragu.dev/gomponents/internal/assert"
+)
+
+func TestTransform(t *testing.T) {
+	t.Run("returns the same tree without rules", func(t *testing.T) {
+		n := g.El("div", g.Attr("class", "hat"), g.El("span", g.Text("party")), g.Group{g.El("br")}, nil)
+		assert.Equal(t, `<div class="hat"><span>party</span><br></div>`, g.Transform(n))
+	})
+
+	t.Run("returns nil for nil node", func(t *testing.T) {
+		if g.Transform(nil) != nil {
+			t.FailNow()
+		}
+	})
+
+	t.Run("rewrites matching nodes at all levels", func(t *testing.T) {
+		n := g.El("div", g.El("img"), g.Group{g.El("p", g.El("img", g.Attr("loading", "eager")))})
+		n = g.Transform(n, g.TransformRule{Match: g.MatchElement("img"), Rewrite: g.SetNodeAttr("loading", "lazy")})
+		assert.Equal(t, `<div><img loading="lazy"><p><img loading="lazy"></p></div>`, n)
+	})
+
+	t.Run("removes nodes when rewrite returns nil", func(t *testing.T) {
+		n := g.Group{g.El("p", g.Text("hat")), g.El("script", g.Raw("alert('party')")), g.Text("!")}
+		n2 := g.Transform(n, g.TransformRule{Match: g.MatchElement("script"), Rewrite: g.RemoveNode})
+		assert.Equal(t, `<p>hat</p>!`, n2)
+	})
+
+	t.Run("applies rules in order, each seeing the result of the previous", func(t *testing.T) {
+		n := g.El("div")
+		n = g.Transform(n,
+			g.TransformRule{Match: g.MatchElement("div"), Rewrite: func(n g.Node) g.Node {
+				return g.El("span", n.(g.ElementNode).Children...)
+			}},
+			g.TransformRule{Match: g.MatchElement("span"), Rewrite: g.SetNodeAttr("class", "hat")},
+		)
+		assert.Equal(t, `<span class="hat"></span>`, n)
+	})
+
+	t.Run("transforms children before their parent", func(t *testing.T) {
+		n := g.El("ul", g.El("li"), g.El("li"))
+		n = g.Transform(n, g.TransformRule{Match: g.MatchElement("ul"), Rewrite: func(n g.Node) g.Node {
+			e := n.(g.ElementNode)
+			return g.El("ul", g.Attr("data-count", strconv.Itoa(len(e.Children))), g.Group(e.Children))
+		}}, g.TransformRule{Match: g.MatchElement("li"), Rewrite: g.RemoveNode})
+		assert.Equal(t, `<ul data-count="0"></ul>`, n)
+	})
+
+	t.Run("does not transform nodes returned by rewrites", func(t *testing.T) {
+		n := g.El("div", g.El("img", g.Attr("src", "/hat.png")))
+		n = g.Transform(n, g.TransformRule{Match: g.MatchElement("img"), Rewrite: func(n g.Node) g.Node {
+			return g.El("figure", n)
+		}})
+		assert.Equal(t, `<div><figure><img src="/hat.png"></figure></div>`, n)
+	})
+
+	t.Run("does not modify the given tree", func(t *testing.T) {
+		n := g.El("div", g.El("img"), g.Attr("class", "hat"))
+		_ = g.Transform(n,
+			g.TransformRule{Match: g.MatchElement("img"), Rewrite: g.SetNodeAttr("alt", "")},
+			g.TransformRule{Match: g.MatchType(g.AttributeType), Rewrite: g.RemoveNode},
+		)
+		assert.Equal(t, `<div class="hat"><img></div>`, n)
+	})
+
+	t.Run("keeps opaque nodes", func(t *testing.T) {
+		n := g.El("div", outsider{})
+		assert.Equal(t, `<div>outsider</div>`, g.Transform(n, g.TransformRule{Match: g.MatchElement("span"), Rewrite: g.RemoveNode}))
+	})
+}
+
+func TestMatchers(t *testing.T) {
+	t.Run("MatchHasAttr matches elements with the attribute, including in groups", func(t *testing.T) {
+		m := g.MatchHasAttr("href")
+		if !m(g.El("a", g.Attr("href", "/"))) || !m(g.El("a", g.Group{g.Attr("href", "/")})) {
+			t.Fatal("did not match")
+		}
+		if m(g.El("a", g.El("span", g.Attr("href", "/")))) || m(g.Attr("href", "/")) {
+			t.Fatal("matched")
+		}
+	})
+
+	t.Run("MatchType matches node types, defaulting to element type", func(t *testing.T) {
+		if !g.MatchType(g.AttributeType)(g.Attr("id", "hat")) || g.MatchType(g.AttributeType)(g.Text("hat")) {
+			t.Fatal("unexpected attribute type match")
+		}
+		if !g.MatchType(g.ElementType)(outsider{}) {
+			t.Fatal("did not match outsider")
+		}
+	})
+
+	t.Run("MatchAll matches only if all matchers match", func(t *testing.T) {
+		m := g.MatchAll(g.MatchElement("a"), g.MatchHasAttr("href"))
+		if !m(g.El("a", g.Attr("href", "/"))) || m(g.El("a")) || m(g.El("div", g.Attr("href", "/"))) {
+			t.FailNow()
+		}
+	})
+}
+
+func TestSetNodeAttr(t *testing.T) {
+	t.Run("replaces existing attributes with the same name", func(t *testing.T) {
+		n := g.SetNodeAttr("rel", "noopener")(g.El("a", g.Attr("rel", "nofollow"), g.Group{g.Attr("rel")}, g.Text("hat")))
+		assert.Equal(t, `<a rel="noopener">hat</a>`, n)
+	})
+
+	t.Run("sets boolean attributes", func(t *testing.T) {
+		n := g.SetNodeAttr("required")(g.El("input"))
+		assert.Equal(t, `<input required>`, n)
+	})
+
+	t.Run("returns other nodes unchanged", func(t *testing.T) {
+		n := g.SetNodeAttr("rel", "noopener")(g.Text("hat"))
+		assert.Equal(t, `hat`, n)
+	})
+}
+
+func TestElementNode_Attribute(t *testing.T) {
+	t.Run("returns the first attribute with the name", func(t *testing.T) {
+		e := g.El("a", g.Group{g.Attr("href", "/hat")}, g.Attr("href", "/party")).(g.ElementNode)
+		a, ok := e.Attribute("href")
+		if !ok || a.Value != "/hat" {
+			t.Fatal("unexpected attribute", a)
+		}
+	})
+
+	t.Run("returns false if there is no attribute with the name", func(t *testing.T) {
+		e := g.El("a").(g.ElementNode)
+		if _, ok := e.Attribute("href"); ok {
+			t.FailNow()
+		}
+	})
+}
+
+func ExampleTransform() {
+	isExternalLink := func(n g.Node) bool {
+		href, _ := n.(g.ElementNode).Attribute("href")
+		return strings.HasPrefix(href.Value, "https://")
+	}
+
+	n := g.El("div",
+		g.El("a", g.Attr("href", "/hats"), g.Text("Hats")),
+		g.El("a", g.Attr("href", "https://example.com"), g.Text("Example")),
+		g.El("img", g.Attr("src", "/hat.jpg")),
+		g.El("script", g.Raw("alert('party')")),
+	)
+
+	n = g.Transform(n,
+		g.TransformRule{Match: g.MatchAll(g.MatchElement("a"), isExternalLink), Rewrite: g.SetNodeAttr("rel", "noopener")},
+		g.TransformRule{Match: g.MatchElement("img"), Rewrite: g.SetNodeAttr("loading", "lazy")},
+		g.TransformRule{Match: g.MatchElement("script"), Rewrite: g.RemoveNode},
+	)
+
+	_ = n.Render(os.Stdout)
+	// Output: <div><a href="/hats">Hats</a><a href="https://example.com" rel="noopener">Example</a><img src="/hat.jpg" loading="lazy"></div>
+}
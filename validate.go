@@ -0,0 +1,179 @@
+package gomponents
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ValidationViolation of a structural HTML rule, found by [RenderValidate] and [ValidateNode].
+// Path is the names of the elements from the root to the element with the violation, including that element.
+type ValidationViolation struct {
+	Path    []string
+	Message string
+}
+
+// String satisfies [fmt.Stringer].
+func (v ValidationViolation) String() string {
+	return strings.Join(v.Path, " > ") + ": " + v.Message
+}
+
+// RenderValidate renders the [Node] n to the writer w like Render, and checks the tree for structural HTML
+// rules that browsers would otherwise silently repair, changing the document. See [ValidateNode] for the rules.
+// The violations are returned in document order. The error is from rendering only.
+func RenderValidate(w io.Writer, n Node) ([]ValidationViolation, error) {
+	if n == nil {
+		return nil, nil
+	}
+
+	if err := n.Render(w); err != nil {
+		return nil, err
+	}
+	return ValidateNode(n), nil
+}
+
+// ValidateNode checks the [Node] tree rooted at n for structural HTML rules,
+// and returns the violations in document order.
+// It's meant for development builds and tests, for example to fail CI on invalid markup.
+//
+// The rules are:
+//   - p elements cannot contain block elements like div, because browsers close the p element before them.
+//   - li elements must be children of ul, ol, or menu elements.
+//   - a elements cannot be nested in other a elements, and form elements not in other form elements.
+//   - id attribute values must be unique.
+//   - Void elements like br cannot have children other than attributes, because [El] ignores them.
+//   - Element and attribute names cannot contain whitespace, quotes, or the characters "<", ">", "/", and "=".
+//
+// Nodes that can't be inspected, like [NodeFunc], are not checked. See [Walk].
+func ValidateNode(n Node) []ValidationViolation {
+	v := validator{ids: map[string]bool{}}
+	v.node(n)
+	return v.violations
+}
+
+// validator of structural HTML rules.
+type validator struct {
+	path       []string
+	ids        map[string]bool
+	violations []ValidationViolation
+}
+
+func (v *validator) node(n Node) {
+	switch n := n.(type) {
+	case ElementNode:
+		v.element(n)
+	case AttributeNode:
+		v.attribute(n)
+	case Group:
+		for _, c := range n {
+			v.node(c)
+		}
+	}
+}
+
+func (v *validator) element(e ElementNode) {
+	v.path = append(v.path, e.Name)
+	defer func() {
+		v.path = v.path[:len(v.path)-1]
+	}()
+
+	if !isValidName(e.Name) {
+		v.report("invalid element name %q", e.Name)
+	}
+
+	switch {
+	case e.Name == "li" && !v.parentIs("ul", "ol", "menu"):
+		v.report("li element must be a child of a ul, ol, or menu element")
+	case (e.Name == "a" || e.Name == "form") && v.ancestorIs(e.Name):
+		v.report("%v element cannot be nested in another %v element", e.Name, e.Name)
+	case isParagraphClosingElement(e.Name) && v.ancestorIs("p"):
+		v.report("p element cannot contain %v element", e.Name)
+	}
+
+	void := isVoidElement(e.Name)
+	if void && len(elementChildren(e.Children)) > 0 {
+		v.report("void element %v cannot have children", e.Name)
+	}
+
+	for _, c := range flatten(e.Children) {
+		// Children of void elements are not rendered, so don't check them
+		if _, ok := c.(AttributeNode); void && !ok {
+			continue
+		}
+		v.node(c)
+	}
+}
+
+func (v *validator) attribute(a AttributeNode) {
+	if !isValidName(a.Name) {
+		v.report("invalid attribute name %q", a.Name)
+	}
+
+	if a.Name == "id" && !a.Boolean {
+		if v.ids[a.Value] {
+			v.report("duplicate id %q", a.Value)
+		}
+		v.ids[a.Value] = true
+	}
+}
+
+// report a violation at the current path.
+func (v *validator) report(format string, a ...interface{}) {
+	path := make([]string, len(v.path))
+	copy(path, v.path)
+	v.violations = append(v.violations, ValidationViolation{Path: path, Message: fmt.Sprintf(format, a...)})
+}
+
+// parentIs reports whether the parent of the current element has one of the given names.
+func (v *validator) parentIs(names ...string) bool {
+	if len(v.path) < 2 {
+		return false
+	}
+	parent := v.path[len(v.path)-2]
+	for _, name := range names {
+		if parent == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ancestorIs reports whether any ancestor of the current element has the given name.
+func (v *validator) ancestorIs(name string) bool {
+	for _, ancestor := range v.path[:len(v.path)-1] {
+		if ancestor == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isValidName reports whether the element or attribute name can be rendered without breaking the HTML.
+func isValidName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		switch r {
+		case ' ', '\t', '\n', '\r', '\f', '"', '\'', '<', '>', '/', '=':
+			return false
+		}
+		if r < 0x20 || r == 0x7f {
+			return false
+		}
+	}
+	return true
+}
+
+// isParagraphClosingElement reports whether the named element is a block element that closes an open p element
+// when the HTML is parsed.
+// See https://html.spec.whatwg.org/multipage/grouping-content.html#the-p-element
+func isParagraphClosingElement(name string) bool {
+	switch name {
+	case "address", "article", "aside", "blockquote", "details", "dialog", "div", "dl", "fieldset", "figcaption",
+		"figure", "footer", "form", "h1", "h2", "h3", "h4", "h5", "h6", "header", "hgroup", "hr", "main", "menu", "nav",
+		"ol", "p", "pre", "search", "section", "table", "ul":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package gomponents_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	g "maragu.dev/gomponents"
+	"maragu.dev/gomponents/internal/assert"
+)
+
+func TestValidateNode(t *testing.T) {
+	tests := []struct {
+		name     string
+		node     g.Node
+		expected []string
+	}{
+		{
+			name: "valid tree",
+			node: g.El("div", g.Attr("id", "hat"),
+				g.El("p", g.El("span", g.Text("party")), g.El("a", g.Attr("href", "/"))),
+				g.El("ul", g.El("li"), g.Group{g.El("li")}),
+				g.El("form", g.El("input", g.Attr("id", "party"), g.Attr("required"))),
+				outsider{},
+			),
+		},
+		{
+			name:     "block elements in p",
+			node:     g.El("p", g.El("div"), g.El("span", g.El("ul"))),
+			expected: []string{"p > div: p element cannot contain div element", "p > span > ul: p element cannot contain ul element"},
+		},
+		{
+			name:     "li outside list",
+			node:     g.El("div", g.El("li"), g.El("ol", g.El("li")), g.El("menu", g.El("li"))),
+			expected: []string{"div > li: li element must be a child of a ul, ol, or menu element"},
+		},
+		{
+			name:     "li at the root",
+			node:     g.El("li"),
+			expected: []string{"li: li element must be a child of a ul, ol, or menu element"},
+		},
+		{
+			name:     "nested a and form",
+			node:     g.El("a", g.El("span", g.El("a")), g.El("form", g.El("div", g.El("form")))),
+			expected: []string{"a > span > a: a element cannot be nested in another a element", "a > form > div > form: form element cannot be nested in another form element"},
+		},
+		{
+			name:     "duplicate ids",
+			node:     g.Group{g.El("div", g.Attr("id", "hat")), g.El("p", g.Group{g.Attr("id", "hat")}), g.El("p", g.Attr("id", "party"))},
+			expected: []string{`p: duplicate id "hat"`},
+		},
+		{
+			name:     "void elements with children",
+			node:     g.El("div", g.El("br", g.Attr("class", "hat"), g.Text("party"), g.El("div", g.Attr("id", "a"), g.El("li")))),
+			expected: []string{"div > br: void element br cannot have children"},
+		},
+		{
+			name:     "invalid names",
+			node:     g.El("my element", g.Attr(`data-"hat"`, "party"), g.Attr("a b"), g.Attr("ok")),
+			expected: []string{`my element: invalid element name "my element"`, `my element: invalid attribute name "data-\"hat\""`, `my element: invalid attribute name "a b"`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var actual []string
+			for _, v := range g.ValidateNode(test.node) {
+				actual = append(actual, v.String())
+			}
+			if strings.Join(actual, "\n") != strings.Join(test.expected, "\n") {
+				t.Fatalf("expected\n%v\nbut got\n%v", strings.Join(test.expected, "\n"), strings.Join(actual, "\n"))
+			}
+		})
+	}
+
+	t.Run("returns the path as element names", func(t *testing.T) {
+		violations := g.ValidateNode(g.El("div", g.El("p", g.El("div"))))
+		if len(violations) != 1 || strings.Join(violations[0].Path, ",") != "div,p,div" || violations[0].Message != "p element cannot contain div element" {
+			t.Fatal("unexpected violations", violations)
+		}
+	})
+}
+
+func TestRenderValidate(t *testing.T) {
+	t.Run("renders and validates", func(t *testing.T) {
+		var b strings.Builder
+		violations, err := g.RenderValidate(&b, g.El("p", g.El("div")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != "<p><div></div></p>" {
+			t.Fatal("unexpected output", b.String())
+		}
+		if len(violations) != 1 {
+			t.Fatal("unexpected violations", violations)
+		}
+	})
+
+	t.Run("does nothing on nil node", func(t *testing.T) {
+		violations, err := g.RenderValidate(&strings.Builder{}, nil)
+		if err != nil || violations != nil {
+			t.FailNow()
+		}
+	})
+
+	t.Run("returns render error on cannot write", func(t *testing.T) {
+		_, err := g.RenderValidate(&erroringWriter{}, g.El("div"))
+		assert.Error(t, err)
+	})
+}
+
+func ExampleValidateNode() {
+	n := g.El("ul",
+		g.El("li", g.El("p", g.Attr("id", "hat"), g.El("div", g.Text("Party hat")))),
+		g.El("li", g.El("br", g.Attr("id", "hat"), g.Text("Ignored"))),
+	)
+	for _, v := range g.ValidateNode(n) {
+		fmt.Println(v)
+	}
+	// Output:
+	// ul > li > p > div: p element cannot contain div element
+	// ul > li > br: void element br cannot have children
+	// ul > li > br: duplicate id "hat"
+}
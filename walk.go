@@ -0,0 +1,43 @@
+package gomponents
+
+// Walk traverses the [Node] tree rooted at n in depth-first order.
+// For each non-nil [Node], enter is called before the children of the [Node] are visited, and leave after.
+// If enter returns false, the children are skipped, but leave is still called for the [Node].
+// Both enter and leave may be nil.
+//
+// Only [ElementNode] and [Group] have children, which includes attributes.
+// All other nodes, like [NodeFunc] and custom [Node] implementations, are visited without children.
+func Walk(n Node, enter func(Node) bool, leave func(Node)) {
+	if n == nil {
+		return
+	}
+
+	if enter == nil || enter(n) {
+		for _, c := range children(n) {
+			Walk(c, enter, leave)
+		}
+	}
+
+	if leave != nil {
+		leave(n)
+	}
+}
+
+// Inspect traverses the [Node] tree rooted at n in depth-first order, calling f for each non-nil [Node].
+// If f returns false, the children of that [Node] are skipped.
+// See [Walk] for which nodes have children.
+func Inspect(n Node, f func(Node) bool) {
+	Walk(n, f, nil)
+}
+
+// children of n, if n is an [ElementNode] or a [Group].
+func children(n Node) []Node {
+	switch n := n.(type) {
+	case ElementNode:
+		return n.Children
+	case Group:
+		return n
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,158 @@
+package gomponents_test
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	g "maragu.dev/gomponents"
+)
+
+// describe a node for test output.
+func describe(n g.Node) string {
+	switch n := n.(type) {
+	case g.ElementNode:
+		return "el:" + n.Name
+	case g.AttributeNode:
+		return "attr:" + n.Name
+	case g.TextNode:
+		return "text:" + string(n)
+	case g.RawNode:
+		return "raw:" + string(n)
+	case g.Group:
+		return "group"
+	default:
+		return "other"
+	}
+}
+
+func TestWalk(t *testing.T) {
+	t.Run("visits all nodes depth-first with enter and leave", func(t *testing.T) {
+		n := g.El("div", g.Attr("class", "hat"),
+			g.El("span", g.Text("party")),
+			g.Group{g.Raw("<br>"), nil, g.Attr("required")},
+			nil,
+		)
+
+		var events []string
+		g.Walk(n, func(n g.Node) bool {
+			events = append(events, "enter "+describe(n))
+			return true
+		}, func(n g.Node) {
+			events = append(events, "leave "+describe(n))
+		})
+
+		expected := strings.Join([]string{
+			"enter el:div",
+			"enter attr:class", "leave attr:class",
+			"enter el:span",
+			"enter text:party", "leave text:party",
+			"leave el:span",
+			"enter group",
+			"enter raw:<br>", "leave raw:<br>",
+			"enter attr:required", "leave attr:required",
+			"leave group",
+			"leave el:div",
+		}, "\n")
+		if actual := strings.Join(events, "\n"); actual != expected {
+			t.Fatalf("expected\n%v\nbut got\n%v", expected, actual)
+		}
+	})
+
+	t.Run("skips children if enter returns false, but still calls leave", func(t *testing.T) {
+		n := g.El("div", g.El("span", g.Text("party")), g.El("p"))
+
+		var events []string
+		g.Walk(n, func(n g.Node) bool {
+			events = append(events, "enter "+describe(n))
+			return describe(n) != "el:span"
+		}, func(n g.Node) {
+			events = append(events, "leave "+describe(n))
+		})
+
+		expected := "enter el:div,enter el:span,leave el:span,enter el:p,leave el:p,leave el:div"
+		if actual := strings.Join(events, ","); actual != expected {
+			t.Fatal("got", actual)
+		}
+	})
+
+	t.Run("visits opaque nodes without children", func(t *testing.T) {
+		n := g.El("div", outsider{}, g.NodeFunc(func(w io.Writer) error { return nil }))
+
+		var events []string
+		g.Walk(n, nil, func(n g.Node) {
+			events = append(events, describe(n))
+		})
+
+		if actual := strings.Join(events, ","); actual != "other,other,el:div" {
+			t.Fatal("got", actual)
+		}
+	})
+
+	t.Run("does nothing on nil node", func(t *testing.T) {
+		g.Walk(nil, func(g.Node) bool {
+			t.Fatal("called enter")
+			return true
+		}, func(g.Node) {
+			t.Fatal("called leave")
+		})
+	})
+}
+
+func ExampleWalk() {
+	n := g.El("ul",
+		g.El("li", g.Text("Party hat")),
+		g.El("li", g.Text("Turtle hat")),
+	)
+
+	var depth int
+	g.Walk(n, func(n g.Node) bool {
+		if e, ok := n.(g.ElementNode); ok {
+			fmt.Println(strings.Repeat("  ", depth) + e.Name)
+			depth++
+		}
+		return true
+	}, func(n g.Node) {
+		if _, ok := n.(g.ElementNode); ok {
+			depth--
+		}
+	})
+	// Output:
+	// ul
+	//   li
+	//   li
+}
+
+func TestInspect(t *testing.T) {
+	t.Run("visits all nodes until f returns false", func(t *testing.T) {
+		n := g.El("div", g.El("span", g.Text("party")), g.El("p", g.Text("hat")))
+
+		var visited []string
+		g.Inspect(n, func(n g.Node) bool {
+			visited = append(visited, describe(n))
+			return describe(n) != "el:p"
+		})
+
+		if actual := strings.Join(visited, ","); actual != "el:div,el:span,text:party,el:p" {
+			t.Fatal("got", actual)
+		}
+	})
+}
+
+func ExampleInspect() {
+	n := g.El("nav",
+		g.El("a", g.Attr("href", "/"), g.Text("Home")),
+		g.El("a", g.Attr("href", "/about"), g.Text("About")),
+	)
+
+	g.Inspect(n, func(n g.Node) bool {
+		if a, ok := n.(g.AttributeNode); ok && a.Name == "href" {
+			fmt.Println(a.Value)
+		}
+		return true
+	})
+	// Output:
+	// /
+	// /about
+}
@@ -0,0 +1,223 @@
+// Package htmx provides attributes for htmx, see https://htmx.org
+//
+// This is an experimental package and does not have the same
+// compatibility guarantees as the core gomponents library.
+package htmx
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	g "maragu.dev/gomponents"
+)
+
+// HxGet issues a GET request to the URL.
+func HxGet(url string) g.Node {
+	return g.Attr("hx-get", url)
+}
+
+// HxPost issues a POST request to the URL.
+func HxPost(url string) g.Node {
+	return g.Attr("hx-post", url)
+}
+
+// HxPut issues a PUT request to the URL.
+func HxPut(url string) g.Node {
+	return g.Attr("hx-put", url)
+}
+
+// HxPatch issues a PATCH request to the URL.
+func HxPatch(url string) g.Node {
+	return g.Attr("hx-patch", url)
+}
+
+// HxDelete issues a DELETE request to the URL.
+func HxDelete(url string) g.Node {
+	return g.Attr("hx-delete", url)
+}
+
+// HxTarget is the CSS selector of the element to swap the response into, or an extended selector like "closest tr".
+func HxTarget(selector string) g.Node {
+	return g.Attr("hx-target", selector)
+}
+
+// HxSelect is the CSS selector of the part of the response to swap in.
+func HxSelect(selector string) g.Node {
+	return g.Attr("hx-select", selector)
+}
+
+// HxInclude is the CSS selector of additional elements whose values are included in the request.
+func HxInclude(selector string) g.Node {
+	return g.Attr("hx-include", selector)
+}
+
+// HxIndicator is the CSS selector of the element that gets the htmx-request class during the request.
+func HxIndicator(selector string) g.Node {
+	return g.Attr("hx-indicator", selector)
+}
+
+// HxPushURL pushes the URL into the browser history. Use "true" for the request URL, or "false" to not push.
+func HxPushURL(v string) g.Node {
+	return g.Attr("hx-push-url", v)
+}
+
+// HxConfirm shows a confirm dialog with the message before the request.
+func HxConfirm(message string) g.Node {
+	return g.Attr("hx-confirm", message)
+}
+
+// HxBoost links and forms in the element to use htmx requests, or not if v is false.
+func HxBoost(v bool) g.Node {
+	return g.Attr("hx-boost", strconv.FormatBool(v))
+}
+
+// HxOn handles the event with the trusted script, like "hx-on:click".
+// Use a leading colon for htmx events, like ":after-request" for "hx-on::after-request".
+// Use [g.JSString] to embed user-controlled data in the script.
+func HxOn(event string, script g.TrustedScript) g.Node {
+	return g.Attr("hx-on:"+event, string(script))
+}
+
+// HxVals adds the values to the request, rendered as JSON.
+// It panics if the values can't be marshalled to JSON, like channels and functions.
+func HxVals(values map[string]interface{}) g.Node {
+	return g.Attr("hx-vals", mustMarshalJSON(values))
+}
+
+// HxHeaders adds the headers to the request, rendered as JSON.
+func HxHeaders(headers map[string]string) g.Node {
+	return g.Attr("hx-headers", mustMarshalJSON(headers))
+}
+
+func mustMarshalJSON(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		panic("cannot marshal to JSON: " + err.Error())
+	}
+	return string(b)
+}
+
+// Swap strategy for [HxSwap].
+type Swap string
+
+const (
+	SwapInnerHTML   = Swap("innerHTML")
+	SwapOuterHTML   = Swap("outerHTML")
+	SwapTextContent = Swap("textContent")
+	SwapBeforeBegin = Swap("beforebegin")
+	SwapAfterBegin  = Swap("afterbegin")
+	SwapBeforeEnd   = Swap("beforeend")
+	SwapAfterEnd    = Swap("afterend")
+	SwapDelete      = Swap("delete")
+	SwapNone        = Swap("none")
+)
+
+// HxSwap is how the response is swapped in, with optional modifiers like "swap:1s", "scroll:top", or "transition:true".
+func HxSwap(s Swap, modifiers ...string) g.Node {
+	return g.Attr("hx-swap", strings.Join(append([]string{string(s)}, modifiers...), " "))
+}
+
+// HxTrigger is what triggers the request, like the "click" event, or more than one, separated by commas.
+// See [Event] and [Every] for creating a [Trigger].
+func HxTrigger(triggers ...Trigger) g.Node {
+	values := make([]string, len(triggers))
+	for i, t := range triggers {
+		values[i] = t.String()
+	}
+	return g.Attr("hx-trigger", strings.Join(values, ", "))
+}
+
+// Trigger for [HxTrigger], created with [Event] or [Every], and with modifiers added by its methods.
+// Each method returns a new Trigger.
+type Trigger struct {
+	event     string
+	filter    string
+	modifiers []string
+}
+
+// Event triggers on the named event, like "click", "change", "load", or "revealed".
+func Event(name string) Trigger {
+	return Trigger{event: name}
+}
+
+// Every triggers by polling with the interval d.
+func Every(d time.Duration) Trigger {
+	return Trigger{event: "every " + formatDuration(d)}
+}
+
+// Filter the event with a JavaScript expression, like "ctrlKey", rendered in square brackets after the event.
+func (t Trigger) Filter(expression string) Trigger {
+	t.filter = expression
+	return t
+}
+
+// Once triggers only the first time.
+func (t Trigger) Once() Trigger {
+	return t.with("once")
+}
+
+// Changed triggers only if the value of the element changed.
+func (t Trigger) Changed() Trigger {
+	return t.with("changed")
+}
+
+// Delay the trigger by d, restarting the delay if the event happens again.
+func (t Trigger) Delay(d time.Duration) Trigger {
+	return t.with("delay:" + formatDuration(d))
+}
+
+// Throttle the trigger to at most once per d.
+func (t Trigger) Throttle(d time.Duration) Trigger {
+	return t.with("throttle:" + formatDuration(d))
+}
+
+// From listens for the event on the elements matching the extended CSS selector instead, like "body" or "closest form".
+func (t Trigger) From(selector string) Trigger {
+	return t.with("from:" + selector)
+}
+
+// Target triggers only if the event target matches the CSS selector.
+func (t Trigger) Target(selector string) Trigger {
+	return t.with("target:" + selector)
+}
+
+// Consume stops the event from triggering requests on parent elements.
+func (t Trigger) Consume() Trigger {
+	return t.with("consume")
+}
+
+// Queue determines which events are queued while a request is in flight: "first", "last", "all", or "none".
+func (t Trigger) Queue(v string) Trigger {
+	return t.with("queue:" + v)
+}
+
+// String satisfies [fmt.Stringer], and is the trigger as used in the hx-trigger attribute.
+func (t Trigger) String() string {
+	var b strings.Builder
+	b.WriteString(t.event)
+	if t.filter != "" {
+		b.WriteString("[" + t.filter + "]")
+	}
+	for _, m := range t.modifiers {
+		b.WriteString(" " + m)
+	}
+	return b.String()
+}
+
+// with a copy of the modifiers and the new modifier, so the original [Trigger] can be reused.
+func (t Trigger) with(modifier string) Trigger {
+	modifiers := make([]string, len(t.modifiers), len(t.modifiers)+1)
+	copy(modifiers, t.modifiers)
+	t.modifiers = append(modifiers, modifier)
+	return t
+}
+
+// formatDuration d in seconds if possible, and in milliseconds otherwise, like htmx expects.
+func formatDuration(d time.Duration) string {
+	if d%time.Second == 0 {
+		return strconv.FormatInt(int64(d/time.Second), 10) + "s"
+	}
+	return strconv.FormatInt(d.Milliseconds(), 10) + "ms"
+}
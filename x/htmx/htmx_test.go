@@ -0,0 +1,132 @@
+package htmx_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	g "maragu.dev/gomponents"
+	. "maragu.dev/gomponents/html"
+	"maragu.dev/gomponents/internal/assert"
+	. "maragu.dev/gomponents/x/htmx"
+)
+
+func TestSimpleAttributes(t *testing.T) {
+	tests := []struct {
+		Name string
+		Func func(string) g.Node
+	}{
+		{Name: "hx-confirm", Func: HxConfirm},
+		{Name: "hx-delete", Func: HxDelete},
+		{Name: "hx-get", Func: HxGet},
+		{Name: "hx-include", Func: HxInclude},
+		{Name: "hx-indicator", Func: HxIndicator},
+		{Name: "hx-patch", Func: HxPatch},
+		{Name: "hx-post", Func: HxPost},
+		{Name: "hx-push-url", Func: HxPushURL},
+		{Name: "hx-put", Func: HxPut},
+		{Name: "hx-select", Func: HxSelect},
+		{Name: "hx-target", Func: HxTarget},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			n := g.El("div", test.Func("hat"))
+			assert.Equal(t, fmt.Sprintf(`<div %v="hat"></div>`, test.Name), n)
+		})
+	}
+}
+
+func TestHxBoost(t *testing.T) {
+	t.Run("renders true or false", func(t *testing.T) {
+		assert.Equal(t, ` hx-boost="true"`, HxBoost(true))
+		assert.Equal(t, ` hx-boost="false"`, HxBoost(false))
+	})
+}
+
+func TestHxOn(t *testing.T) {
+	t.Run("returns an attribute which name is prefixed with hx-on:", func(t *testing.T) {
+		assert.Equal(t, ` hx-on::after-request="this.reset()"`, HxOn(":after-request", "this.reset()"))
+	})
+}
+
+func TestHxVals(t *testing.T) {
+	t.Run("renders the values as JSON with sorted keys", func(t *testing.T) {
+		n := HxVals(map[string]interface{}{"party": true, "hats": 3, "color": "<red>"})
+		assert.Equal(t, ` hx-vals="{&#34;color&#34;:&#34;\u003cred\u003e&#34;,&#34;hats&#34;:3,&#34;party&#34;:true}"`, n)
+	})
+
+	t.Run("panics if the values can't be marshalled", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("expected a panic")
+			}
+		}()
+		HxVals(map[string]interface{}{"hat": make(chan int)})
+	})
+}
+
+func TestHxHeaders(t *testing.T) {
+	t.Run("renders the headers as JSON", func(t *testing.T) {
+		assert.Equal(t, ` hx-headers="{&#34;X-Party&#34;:&#34;hat&#34;}"`, HxHeaders(map[string]string{"X-Party": "hat"}))
+	})
+}
+
+func TestHxSwap(t *testing.T) {
+	t.Run("renders the swap strategy", func(t *testing.T) {
+		assert.Equal(t, ` hx-swap="outerHTML"`, HxSwap(SwapOuterHTML))
+	})
+
+	t.Run("renders the swap strategy with modifiers", func(t *testing.T) {
+		assert.Equal(t, ` hx-swap="beforeend swap:1s scroll:bottom"`, HxSwap(SwapBeforeEnd, "swap:1s", "scroll:bottom"))
+	})
+}
+
+func TestHxTrigger(t *testing.T) {
+	tests := []struct {
+		name     string
+		triggers []Trigger
+		expected string
+	}{
+		{name: "event", triggers: []Trigger{Event("click")}, expected: `click`},
+		{name: "polling", triggers: []Trigger{Every(2 * time.Second)}, expected: `every 2s`},
+		{name: "filter", triggers: []Trigger{Event("click").Filter("ctrlKey")}, expected: `click[ctrlKey]`},
+		{
+			name:     "modifiers",
+			triggers: []Trigger{Event("keyup").Changed().Delay(500 * time.Millisecond).Throttle(time.Second).Once()},
+			expected: `keyup changed delay:500ms throttle:1s once`,
+		},
+		{
+			name:     "selectors",
+			triggers: []Trigger{Event("click").From("body").Target(".hat").Consume().Queue("last")},
+			expected: `click from:body target:.hat consume queue:last`,
+		},
+		{name: "multiple triggers", triggers: []Trigger{Event("load"), Event("revealed").Once()}, expected: `load, revealed once`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, fmt.Sprintf(` hx-trigger="%v"`, test.expected), HxTrigger(test.triggers...))
+		})
+	}
+
+	t.Run("does not change the original trigger", func(t *testing.T) {
+		keyup := Event("keyup").Changed()
+		_ = keyup.Delay(time.Second)
+		_ = keyup.Once()
+		if keyup.String() != "keyup changed" {
+			t.Fatal("unexpected trigger", keyup)
+		}
+	})
+}
+
+func Example() {
+	_ = Input(Type("search"), Name("q"),
+		HxGet("/search"),
+		HxTrigger(Event("input").Changed().Delay(500*time.Millisecond), Event("search")),
+		HxTarget("#results"),
+		HxSwap(SwapInnerHTML),
+	).Render(os.Stdout)
+	// Output: <input type="search" name="q" hx-get="/search" hx-trigger="input changed delay:500ms, search" hx-target="#results" hx-swap="innerHTML">
+}